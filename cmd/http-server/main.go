@@ -1,32 +1,195 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"wikiviews/internal/aggregate"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/auth"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/config"
+	"wikiviews/internal/edits"
+	"wikiviews/internal/health"
+	"wikiviews/internal/httpclient"
+	"wikiviews/internal/logging"
+	"wikiviews/internal/metrics"
 	"wikiviews/internal/pageviews"
+	"wikiviews/internal/top"
+	"wikiviews/internal/tracing"
+	"wikiviews/internal/uniquedevices"
+	"wikiviews/internal/wikimedia"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// How long requests cancelled at the shutdown timeout have to respond before their connections are closed
+	shutdownGrace = time.Second
+	// How long readiness probes reuse the result of checking upstream, so they add little load to Wikimedia
+	upstreamCheckTTL = 30 * time.Second
 )
 
 func main() {
+	// Load settings from the config file given by -config or WIKIVIEWS_CONFIG, overridden by the environment
+	configFile := flag.String("config", os.Getenv(config.EnvConfigFile), "path to a JSON config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		slog.Error("error loading config", "error", err)
+		os.Exit(1)
+	}
+
+	// Log JSON lines at the configured level, including anything still written through the log package
+	level, _ := cfg.Level()
+	slog.SetDefault(logging.New(os.Stdout, level))
+
+	// Trace requests through to upstream calls, exporting spans only when a collector is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Endpoint, cfg.Tracing.SampleRatio)
+	if err != nil {
+		slog.Error("error setting up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	if !cfg.Upstream.HasContact() {
+		slog.Warn("user agent gives no contact details, which Wikimedia policy asks for. Set upstream.user_agent", "user_agent", cfg.Upstream.UserAgent)
+	}
+
 	e := echo.New()
-	// Enable logger
-	e.Use(middleware.Logger())
-	// Rate limit to 20 rps
-	e.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(20)))
+	// Announce the server in the log instead of echo's plain text banner
+	e.HideBanner, e.HidePort = true, true
+	// Derive every request context from one that is cancelled if requests outlast the shutdown timeout,
+	// cancelling their upstream calls along with them
+	requestsCtx, cancelRequests := context.WithCancelCause(context.Background())
+	defer cancelRequests(nil)
+	e.Server.BaseContext = func(net.Listener) context.Context { return requestsCtx }
+	// Render every error in the service's error format
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	// Tag every request with an id, taken from the X-Request-ID request header if set, and returned in the
+	// X-Request-ID response header and in error bodies
+	e.Use(middleware.RequestID())
+	// Serve every request in a span, continuing the trace of any W3C traceparent header
+	e.Use(tracing.Middleware())
+	// Count requests and their latency by route and status, including those the rate limiter rejects
+	e.Use(metrics.Middleware())
+	// Log every request, with a logger tagged with its request id for everything logged while serving it
+	e.Use(logging.Middleware())
+	// Render errors here, inside the middleware above so each sees the status an error was served with, and
+	// outside the middleware below so their rejections are rendered too
+	e.Use(apierror.Middleware())
+	// Require an API key when any are configured, except for probes and metrics, limiting each key to its own
+	// rate and daily quota. Quotas are counted in Redis when set, so they hold across replicas
+	var quotaCounter auth.Counter = auth.NewMemoryCounter()
+	if len(cfg.Cache.RedisAddr) > 0 {
+		quotaCounter = auth.NewRedisCounter(cfg.Cache.RedisAddr)
+	}
+	authenticator := auth.NewAuthenticator(cfg.Auth.Keys, cfg.RateLimit, quotaCounter)
+	if authenticator.Enabled() {
+		slog.Info("requiring API keys", "keys", len(cfg.Auth.Keys))
+	}
+	e.Use(authenticator.Identify())
+	// Rate limit requests per second from each client IP address, counting rejections. Requests authenticated
+	// with an API key are limited by their key instead, but those without a valid key are limited before they
+	// are refused, so keys cannot be guessed at any faster
+	e.Use(middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: func(c echo.Context) bool { return len(auth.KeyName(c)) > 0 },
+		Store:   middleware.NewRateLimiterMemoryStore(rate.Limit(cfg.RateLimit)),
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			metrics.RateLimited()
+			return middleware.DefaultRateLimiterConfig.DenyHandler(c, identifier, err)
+		},
+	}))
+	e.Use(authenticator.Middleware("/healthcheck", "/livez", "/readyz", "/metrics"))
+
+	// Cache upstream responses in Redis when a Redis address is set, otherwise in memory
+	var responseCache cache.Cache = cache.NewMemoryCache(cfg.Cache.Capacity)
+	if len(cfg.Cache.RedisAddr) > 0 {
+		responseCache = cache.NewRedisCache(cfg.Cache.RedisAddr)
+	}
+
+	// Share one Wikimedia API client, and its connection pool, across all requests
+	client := wikimedia.NewClient(cfg.Upstream.BaseUrl, httpclient.NewHttpClient(cfg.Upstream), cfg.Upstream.ClientOptions()...)
 
-	pageviewsHandler := pageviews.NewPageviewsHandler()
-	e.GET("/healthcheck", healthcheck)
+	// Stop routing to this pod while Wikimedia or the cache cannot be reached
+	serverHealth := health.NewHealth()
+	serverHealth.Register("upstream", health.Cached(client.Ping, upstreamCheckTTL))
+	serverHealth.Register("cache", responseCache.Ping)
+	e.GET("/healthcheck", serverHealth.Healthcheck)
+	e.GET("/livez", serverHealth.Livez)
+	e.GET("/readyz", serverHealth.Readyz)
+	e.GET("/metrics", metrics.Handler())
+
+	pageviewsHandler := pageviews.NewPageviewsHandler(client, responseCache)
 	e.GET("/pageviews", pageviewsHandler.List)
+	e.POST("/pageviews/batch", pageviewsHandler.Batch)
+
+	topHandler := top.NewTopHandler(client)
+	e.GET("/top", topHandler.List)
+	e.GET("/top/by-country", topHandler.ByCountry)
+	e.GET("/top/per-country", topHandler.PerCountry)
+
+	aggregateHandler := aggregate.NewAggregateHandler(client, responseCache)
+	e.GET("/aggregate", aggregateHandler.List)
+
+	uniqueDevicesHandler := uniquedevices.NewUniqueDevicesHandler(client)
+	e.GET("/unique-devices", uniqueDevicesHandler.List)
+
+	editsHandler := edits.NewEditsHandler(client)
+	e.GET("/edits", editsHandler.List)
 
-	if err := e.Start(":8080"); err != http.ErrServerClosed {
-		log.Fatal(err)
+	// Serve until Kubernetes, or Ctrl-C, asks the server to stop
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	go func() {
+		slog.Info("starting server", "addr", cfg.Addr)
+		if err := e.Start(cfg.Addr); err != http.ErrServerClosed {
+			slog.Error("error starting server", "error", err)
+			os.Exit(1)
+		}
+	}()
+	<-signalCtx.Done()
+	// Let a second signal kill the server outright
+	stop()
+
+	shutdown(e, serverHealth, cfg, cancelRequests)
+
+	// Export spans of the last requests before exiting
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("error flushing traces", "error", err)
 	}
 }
 
-// Healthcheck probe
-// May be used for Kubernetes liveness and readiness probes
-func healthcheck(c echo.Context) error {
-	return c.String(http.StatusOK, "ok")
+// Drain the server: fail /healthcheck and /readyz so no new traffic is routed here, stop accepting connections once load
+// balancers have had time to notice, then wait for in-flight requests up to the shutdown timeout before
+// cancelling whatever is left
+func shutdown(e *echo.Echo, serverHealth *health.Health, cfg *config.Config, cancelRequests context.CancelCauseFunc) {
+	slog.Info("shutting down: draining", "drain_delay", time.Duration(cfg.DrainDelay).String())
+	serverHealth.Drain()
+	time.Sleep(time.Duration(cfg.DrainDelay))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	defer cancel()
+	if err := e.Shutdown(ctx); err != nil {
+		slog.Error("requests outlasted the shutdown timeout, cancelling them", "error", err)
+		cancelRequests(apierror.ErrShuttingDown)
+
+		// Give cancelled requests a moment to respond before closing their connections
+		graceCtx, graceCancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer graceCancel()
+		e.Shutdown(graceCtx)
+		e.Close()
+		return
+	}
+
+	slog.Info("shut down cleanly")
 }
@@ -0,0 +1,140 @@
+// Command wikiviews queries Wikipedia pageviews from the command line, with the same validations, defaults
+// and upstream handling as the /pageviews endpoint of the HTTP server.
+//
+// Usage:
+//
+//	wikiviews [flags] ARTICLE...
+//
+// e.g. wikiviews -start 202401 -end 202403 -format csv Michael_Phelps Katie_Ledecky
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/config"
+	"wikiviews/internal/httpclient"
+	"wikiviews/internal/pageviews"
+	"wikiviews/internal/render"
+	"wikiviews/internal/wikimedia"
+)
+
+// Output formats, led by the default
+var formats = []render.Format{render.Table, render.CSV, render.TSV, render.JSON, render.NDJSON}
+
+// Most responses cached across the articles of one run
+const cacheCapacity = 1000
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Run the command with args, returning its exit code: 0 on success, 1 if any article failed and 2 on a usage error
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("wikiviews", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: wikiviews [flags] ARTICLE...")
+		flags.PrintDefaults()
+	}
+
+	var params pageviews.Params
+	flags.StringVar(&params.Project, "project", "", "Wikimedia project, e.g. de.wikipedia.org (default en.wikipedia.org)")
+	flags.StringVar(&params.Date, "date", "", "single month in form YYYYMM")
+	flags.StringVar(&params.Start, "start", "", "start of a date range in form YYYYMM, YYYYMMDD or YYYYMMDDHH")
+	flags.StringVar(&params.End, "end", "", "end of a date range in form YYYYMM, YYYYMMDD or YYYYMMDDHH")
+	flags.StringVar(&params.Granularity, "granularity", "", "monthly, daily or hourly (default monthly)")
+	flags.StringVar(&params.Access, "access", "", "all-access, desktop, mobile-app or mobile-web (default all-access)")
+	flags.StringVar(&params.Agent, "agent", "", "all-agents, user, spider or automated (default all-agents)")
+	flags.BoolVar(&params.Autocorrect, "autocorrect", false, "retry an article with no results as the top title search candidate")
+	flags.BoolVar(&params.ResolveRedirects, "resolve-redirects", false, "query the article a redirect points at")
+	flags.BoolVar(&params.SumRedirects, "sum-redirects", false, "add the views of every redirect onto the canonical article")
+	format := flags.String("format", string(render.Table), "output format: table, csv, tsv, json or ndjson")
+	verbose := flags.Bool("v", false, "log requests to the Wikipedia API")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	articles := flags.Args()
+	if len(articles) == 0 {
+		fmt.Fprintln(stderr, "error: at least one article is required")
+		flags.Usage()
+		return 2
+	}
+
+	if !slices.Contains(formats, render.Format(*format)) {
+		fmt.Fprintf(stderr, "error: format param %s is invalid: param must be one of table, csv, tsv, json, ndjson\n", *format)
+		return 2
+	}
+
+	// Share validation with the HTTP server so messages never drift between the two
+	if err := params.Validate(context.Background()); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// Log requests to the Wikipedia API as plain text rather than the server's JSON, and only when asked to
+	logOutput := io.Discard
+	if *verbose {
+		logOutput = stderr
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(logOutput, nil)))
+
+	// Stop outstanding requests on Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Reach upstream with the same settings as the server, e.g. the User-Agent from WIKIVIEWS_USER_AGENT
+	cfg, err := config.Load(os.Getenv(config.EnvConfigFile))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	client := wikimedia.NewClient(cfg.Upstream.BaseUrl, httpclient.NewHttpClient(cfg.Upstream), cfg.Upstream.ClientOptions()...)
+	ph := pageviews.NewPageviewsHandler(client, cache.NewMemoryCache(cacheCapacity))
+
+	// Query each article in turn, reporting failures without giving up on the rest
+	code := 0
+	var items []pageviews.Item
+	for _, article := range articles {
+		articleItems, err := ph.Query(ctx, params, article)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", article, message(err))
+			code = 1
+			continue
+		}
+		items = append(items, articleItems...)
+	}
+
+	if err := render.Write(stdout, render.Format(*format), items); err != nil {
+		fmt.Fprintln(stderr, "error writing output:", err)
+		return 1
+	}
+
+	return code
+}
+
+// Return the message the HTTP server would respond with for err, followed by any suggested titles
+func message(err error) string {
+	var e *apierror.Error
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+
+	if len(e.Suggestions) > 0 {
+		return fmt.Sprintf("%s Did you mean: %s?", e.Message, strings.Join(e.Suggestions, ", "))
+	}
+
+	return e.Message
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"wikiviews/internal/pageviews"
+	"wikiviews/internal/wikimedia"
+)
+
+func TestRun_UsageErrors(t *testing.T) {
+	// The message for an invalid param is the one the HTTP server responds with
+	params := pageviews.Params{Date: "202413"}
+	expectedDateMessage := params.Validate(context.Background()).Error()
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, "error: at least one article is required"},
+		{[]string{"-date", "202413", "Orca"}, expectedDateMessage},
+		{[]string{"-date", "202402", "-format", "xml", "Orca"}, "error: format param xml is invalid"},
+		{[]string{"-date", "202402", "-granularity", "weekly", "Orca"}, "error: granularity param weekly is invalid"},
+		{[]string{"-bogus", "Orca"}, "flag provided but not defined: -bogus"},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr strings.Builder
+		code := run(tc.args, &stdout, &stderr)
+
+		if code != 2 {
+			t.Errorf("run(%q) returns exit code %d; Expected 2", tc.args, code)
+		}
+
+		if !strings.Contains(stderr.String(), tc.expected) {
+			t.Errorf("run(%q) writes %q to stderr; Expected it to contain %q", tc.args, stderr.String(), tc.expected)
+		}
+
+		if stdout.Len() > 0 {
+			t.Errorf("run(%q) writes %q to stdout; Expected nothing", tc.args, stdout.String())
+		}
+	}
+}
+
+// Serve monthly views of a few articles as the Wikipedia API would, pointing the command at it.
+// Any other article is not found, and neither is any title search
+func newStubWikimedia(t *testing.T) {
+	views := map[string]int32{"Orca": 10, "Michael_Phelps": 20}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Per-article paths end with /{article}/{granularity}/{start}/{end}
+		segments := strings.Split(r.URL.Path, "/")
+		article := segments[len(segments)-4]
+		if !strings.Contains(r.URL.Path, "/pageviews/per-article/") || views[article] == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"items": []wikimedia.ArticleItem{{
+			Project: "en.wikipedia", Article: article, Granularity: "monthly", Timestamp: "2024020100",
+			Access: "all-access", Agent: "all-agents", Views: views[article],
+		}}})
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("WIKIVIEWS_UPSTREAM_BASE_URL", server.URL)
+	t.Setenv("WIKIVIEWS_UPSTREAM_PROJECT_URL", server.URL+"/{project}/w")
+}
+
+func TestRun_Formats(t *testing.T) {
+	newStubWikimedia(t)
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"table", "" +
+			"PROJECT       ARTICLE         GRANULARITY  TIMESTAMP   ACCESS      AGENT       VIEWS  CANONICAL\n" +
+			"en.wikipedia  Orca            monthly      2024020100  all-access  all-agents  10     \n" +
+			"en.wikipedia  Michael_Phelps  monthly      2024020100  all-access  all-agents  20     \n"},
+		{"csv", "" +
+			"project,article,granularity,timestamp,access,agent,views,canonical\n" +
+			"en.wikipedia,Orca,monthly,2024020100,all-access,all-agents,10,\n" +
+			"en.wikipedia,Michael_Phelps,monthly,2024020100,all-access,all-agents,20,\n"},
+		{"json", "" +
+			`[{"project":"en.wikipedia","article":"Orca","granularity":"monthly","timestamp":"2024020100","access":"all-access","agent":"all-agents","views":10},` +
+			`{"project":"en.wikipedia","article":"Michael_Phelps","granularity":"monthly","timestamp":"2024020100","access":"all-access","agent":"all-agents","views":20}]` + "\n"},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr strings.Builder
+		args := []string{"-date", "202402", "-format", tc.format, "Orca", "Michael_Phelps"}
+		code := run(args, &stdout, &stderr)
+
+		if code != 0 || stderr.Len() > 0 {
+			t.Errorf("run(%q) returns exit code %d and writes %q to stderr; Expected 0 and nothing", args, code, stderr.String())
+		}
+
+		if stdout.String() != tc.expected {
+			t.Errorf("run(%q) writes %q to stdout; Expected %q", args, stdout.String(), tc.expected)
+		}
+	}
+}
+
+func TestRun_PartialFailure(t *testing.T) {
+	newStubWikimedia(t)
+
+	var stdout, stderr strings.Builder
+	args := []string{"-date", "202402", "-format", "csv", "Orca", "Unknown_Article"}
+	code := run(args, &stdout, &stderr)
+
+	// The articles found are still written out, while the rest are reported
+	if code != 1 {
+		t.Errorf("run(%q) returns exit code %d; Expected 1", args, code)
+	}
+
+	expected := "project,article,granularity,timestamp,access,agent,views,canonical\n" +
+		"en.wikipedia,Orca,monthly,2024020100,all-access,all-agents,10,\n"
+	if stdout.String() != expected {
+		t.Errorf("run(%q) writes %q to stdout; Expected %q", args, stdout.String(), expected)
+	}
+
+	if !strings.HasPrefix(stderr.String(), "Unknown_Article: ") || strings.Contains(stderr.String(), "Orca") {
+		t.Errorf("run(%q) writes %q to stderr; Expected only a message for Unknown_Article", args, stderr.String())
+	}
+}
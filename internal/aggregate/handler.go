@@ -0,0 +1,124 @@
+package aggregate
+
+import (
+	"context"
+	"strings"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/paramformatter"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	Params struct {
+		Project     string `query:"project"`
+		Access      string `query:"access"`
+		Agent       string `query:"agent"`
+		Granularity string `query:"granularity"`
+		Date        string `query:"date"`
+
+		// Start and end of the month as sent to the Wikipedia API, set by validate
+		rangeStart string
+		rangeEnd   string
+	}
+
+	AggregateHandler struct {
+		client *wikimedia.Client
+		cache  cache.Cache
+	}
+)
+
+const cacheHeader = "X-Cache"
+
+// List returns pageviews summed across every article of a project for one month
+func (ah *AggregateHandler) List(c echo.Context) (err error) {
+	var params Params
+	if err = c.Bind(&params); err != nil {
+		return
+	}
+
+	if err = params.validate(); err != nil {
+		return apierror.Validation(err)
+	}
+
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+
+	items, hit, err := ah.cachedFetch(c.Request().Context(), params)
+	if err != nil {
+		return apierror.From(err)
+	}
+
+	// Report whether the response was served from the cache
+	if hit {
+		c.Response().Header().Set(cacheHeader, "HIT")
+	} else {
+		c.Response().Header().Set(cacheHeader, "MISS")
+	}
+
+	return render.Items(c, format, items)
+}
+
+// Validate params, filling in defaults and the range boundaries the Wikipedia API needs
+func (p *Params) validate() (err error) {
+	if err = paramvalidator.Project(&p.Project); err != nil {
+		return
+	}
+	if err = paramvalidator.Granularity(&p.Granularity, paramvalidator.NewGranularityValidator()); err != nil {
+		return
+	}
+	if err = paramvalidator.Access(&p.Access); err != nil {
+		return
+	}
+	if err = paramvalidator.Agent(&p.Agent); err != nil {
+		return
+	}
+
+	// Validate date input
+	dv := paramvalidator.NewDateValidator()
+	if _, err = dv.Run(p.Date); err != nil {
+		return
+	}
+
+	// Return the first and last day of the month, down to the hour for hourly data points
+	df := paramformatter.NewDateFormatter()
+	if p.rangeStart, p.rangeEnd, err = df.Run(p.Date); err != nil {
+		return
+	}
+	if p.Granularity == "hourly" {
+		p.rangeStart, p.rangeEnd = p.rangeStart+"00", p.rangeEnd+"23"
+	}
+
+	return
+}
+
+// Query the Wikipedia API, serving the response from the cache when possible
+func (ah *AggregateHandler) cachedFetch(ctx context.Context, params Params) (items []wikimedia.AggregateItem, hit bool, err error) {
+	key := strings.Join([]string{"aggregate", params.Project, params.Access, params.Agent, params.Granularity, params.rangeStart, params.rangeEnd}, "/")
+
+	// Cache the month until it has closed and settled, as for per-article pageviews
+	month, _ := time.Parse("200601", params.Date)
+	ttl := cache.TTL(month.AddDate(0, 1, 0), time.Now())
+
+	return cache.Fetch(ctx, ah.cache, key, ttl, func() ([]wikimedia.AggregateItem, error) {
+		return ah.client.Aggregate(ctx, wikimedia.AggregateRequest{
+			Project:     params.Project,
+			Access:      params.Access,
+			Agent:       params.Agent,
+			Granularity: params.Granularity,
+			Start:       params.rangeStart,
+			End:         params.rangeEnd,
+		})
+	})
+}
+
+func NewAggregateHandler(client *wikimedia.Client, responseCache cache.Cache) *AggregateHandler {
+	return &AggregateHandler{client: client, cache: responseCache}
+}
@@ -0,0 +1,53 @@
+package aggregate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestAggregateHandler_List(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"items":[{"project":"en.wikipedia","access":"all-access","agent":"all-agents","granularity":"monthly","timestamp":"2024020100","views":7500000000}]}`))
+	}))
+	defer server.Close()
+
+	e := echo.New()
+	ah := NewAggregateHandler(wikimedia.NewClient(server.URL, server.Client()), cache.NewMemoryCache(10))
+	e.GET("/aggregate", ah.List)
+
+	testCases := []struct {
+		query        string
+		expectedPath string
+		expectedHit  string
+	}{
+		{"date=202402", "/pageviews/aggregate/en.wikipedia.org/all-access/all-agents/monthly/20240201/20240229", "MISS"},
+		{"date=202402", "", "HIT"},
+		{"date=202402&granularity=hourly&agent=user", "/pageviews/aggregate/en.wikipedia.org/all-access/user/hourly/2024020100/2024022923", "MISS"},
+	}
+
+	for _, tc := range testCases {
+		paths = nil
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/aggregate?"+tc.query, nil))
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"views":7500000000`) {
+			t.Errorf("AggregateHandler.List(%q) returns %d %s; Expected 200 with aggregate views", tc.query, rec.Code, rec.Body)
+		}
+
+		if hit := rec.Header().Get(cacheHeader); hit != tc.expectedHit {
+			t.Errorf("AggregateHandler.List(%q) returns %s = %q; Expected %q", tc.query, cacheHeader, hit, tc.expectedHit)
+		}
+
+		if len(tc.expectedPath) > 0 && (len(paths) != 1 || paths[0] != tc.expectedPath) {
+			t.Errorf("AggregateHandler.List(%q) requests paths %v; Expected %q", tc.query, paths, tc.expectedPath)
+		}
+	}
+}
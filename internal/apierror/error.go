@@ -0,0 +1,123 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"wikiviews/internal/metrics"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/wikimedia"
+)
+
+// Error is the error body returned by every endpoint, e.g.
+// {"code":"invalid_param","error":"error: date param is invalid: ...","request_id":"..."}.
+// The message is kept under the "error" key so clients of the original {"error": "..."} body keep working
+type Error struct {
+	// Status is the HTTP status to respond with
+	Status int `json:"-"`
+	// Code is a stable machine-readable name for the kind of error, e.g. invalid_param or upstream_timeout
+	Code    string `json:"code"`
+	Message string `json:"error"`
+	// UpstreamStatus is the status the Wikimedia API responded with, when the error came from upstream
+	UpstreamStatus int    `json:"upstream_status,omitempty"`
+	RequestID      string `json:"request_id,omitempty"`
+	// Suggestions are alternative params to try, e.g. real article titles for an article with no results
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+const (
+	CodeInvalidParam        = "invalid_param"
+	CodeNotFound            = "not_found"
+	CodeArticleNotFound     = "article_not_found"
+	CodeRateLimited         = "rate_limited"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeQuotaExceeded       = "quota_exceeded"
+	CodeUpstreamRateLimited = "upstream_rate_limited"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeUpstreamError       = "upstream_error"
+	CodeUpstreamTimeout     = "upstream_timeout"
+	CodeUpstreamDecode      = "upstream_decode_error"
+	CodeClientClosed        = "client_closed_request"
+	CodeShuttingDown        = "shutting_down"
+	CodeInternal            = "internal_error"
+)
+
+// ErrShuttingDown is the cause of cancelling requests that outlast the server's shutdown timeout
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Status for a request the client closed before a response was written, as popularized by nginx
+const StatusClientClosedRequest = 499
+
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Validation wraps a validator error as a 400, counting it in metrics by the param and reason of a
+// *paramvalidator.Error, or as an invalid unknown param for any other error
+func Validation(err error) *Error {
+	param, reason := "unknown", paramvalidator.ReasonInvalid
+	var validationErr *paramvalidator.Error
+	if errors.As(err, &validationErr) {
+		param, reason = validationErr.Param, validationErr.Reason
+	}
+	metrics.ValidationFailed(param, reason)
+
+	return New(http.StatusBadRequest, CodeInvalidParam, err.Error())
+}
+
+// From maps any error onto the error model. An *Error is returned as is, Wikimedia client errors are mapped
+// by failure mode, and anything else is an internal error
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var upstreamErr *wikimedia.APIError
+	if errors.As(err, &upstreamErr) {
+		e := fromUpstream(upstreamErr)
+		e.UpstreamStatus = upstreamErr.StatusCode
+		return e
+	}
+
+	switch {
+	case errors.Is(err, ErrShuttingDown):
+		return New(http.StatusServiceUnavailable, CodeShuttingDown, "error: server is shutting down. Please retry")
+	case errors.Is(err, wikimedia.ErrCircuitOpen):
+		return New(http.StatusServiceUnavailable, CodeUpstreamUnavailable, "error: Wikipedia API is temporarily unavailable. Please retry later")
+	case errors.Is(err, wikimedia.ErrDecode):
+		return New(http.StatusBadGateway, CodeUpstreamDecode, "error: Wikipedia API returned a response that could not be decoded")
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(http.StatusGatewayTimeout, CodeUpstreamTimeout, "error: Wikipedia API did not respond in time. Please retry later")
+	case errors.Is(err, context.Canceled):
+		return New(StatusClientClosedRequest, CodeClientClosed, "error: request was cancelled by the client")
+	case errors.Is(err, wikimedia.ErrTransport):
+		return New(http.StatusBadGateway, CodeUpstreamError, "error: Wikipedia API could not be reached. Please retry later")
+	}
+
+	return New(http.StatusInternalServerError, CodeInternal, "error: internal server error")
+}
+
+func fromUpstream(err *wikimedia.APIError) *Error {
+	switch {
+	case errors.Is(err, wikimedia.ErrNotFound):
+		return New(http.StatusNotFound, CodeNotFound, "error: Wikipedia API did not return any results")
+	case errors.Is(err, wikimedia.ErrRateLimited):
+		return New(http.StatusServiceUnavailable, CodeUpstreamRateLimited, "error: Wikipedia API is rate limiting requests. Please retry later")
+	case errors.Is(err, wikimedia.ErrServer):
+		return New(http.StatusBadGateway, CodeUpstreamError, "error: Wikipedia API returned a server error. Please retry later")
+	}
+
+	// Any other 4xx means upstream rejected the query itself, e.g. a date before its data begins
+	message := fmt.Sprintf("error: Wikipedia API rejected the query with status %d", err.StatusCode)
+	if len(err.Detail) > 0 {
+		message = fmt.Sprintf("%s: %s", message, err.Detail)
+	}
+	return New(http.StatusBadRequest, CodeInvalidParam, message)
+}
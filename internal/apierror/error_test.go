@@ -0,0 +1,65 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"wikiviews/internal/metrics/metricstest"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/wikimedia"
+)
+
+func TestFrom(t *testing.T) {
+	testCases := []struct {
+		err            error
+		status         int
+		code           string
+		upstreamStatus int
+	}{
+		{New(http.StatusNotFound, CodeArticleNotFound, "error: no results"), http.StatusNotFound, CodeArticleNotFound, 0},
+		{fmt.Errorf("wrapped: %w", Validation(errors.New("error: date param is invalid"))), http.StatusBadRequest, CodeInvalidParam, 0},
+		{&wikimedia.APIError{StatusCode: http.StatusNotFound}, http.StatusNotFound, CodeNotFound, http.StatusNotFound},
+		{&wikimedia.APIError{StatusCode: http.StatusTooManyRequests}, http.StatusServiceUnavailable, CodeUpstreamRateLimited, http.StatusTooManyRequests},
+		{&wikimedia.APIError{StatusCode: http.StatusInternalServerError}, http.StatusBadGateway, CodeUpstreamError, http.StatusInternalServerError},
+		{&wikimedia.APIError{StatusCode: http.StatusServiceUnavailable}, http.StatusBadGateway, CodeUpstreamError, http.StatusServiceUnavailable},
+		{&wikimedia.APIError{StatusCode: http.StatusBadRequest}, http.StatusBadRequest, CodeInvalidParam, http.StatusBadRequest},
+		{wikimedia.ErrCircuitOpen, http.StatusServiceUnavailable, CodeUpstreamUnavailable, 0},
+		{fmt.Errorf("%w: unexpected end of JSON input", wikimedia.ErrDecode), http.StatusBadGateway, CodeUpstreamDecode, 0},
+		{fmt.Errorf("%w: %w", wikimedia.ErrTransport, context.DeadlineExceeded), http.StatusGatewayTimeout, CodeUpstreamTimeout, 0},
+		{fmt.Errorf("%w: dial tcp: no such host", wikimedia.ErrTransport), http.StatusBadGateway, CodeUpstreamError, 0},
+		{context.Canceled, StatusClientClosedRequest, CodeClientClosed, 0},
+		{errors.New("boom"), http.StatusInternalServerError, CodeInternal, 0},
+	}
+
+	for _, tc := range testCases {
+		e := From(tc.err)
+
+		if e.Status != tc.status || e.Code != tc.code || e.UpstreamStatus != tc.upstreamStatus {
+			t.Errorf("From(%v) returns status %d, code %q, upstream status %d; Expected %d, %q, %d", tc.err, e.Status, e.Code, e.UpstreamStatus, tc.status, tc.code, tc.upstreamStatus)
+		}
+	}
+}
+
+func TestValidation(t *testing.T) {
+	testCases := []struct {
+		err            error
+		expectedParam  string
+		expectedReason string
+	}{
+		{paramvalidator.NewError("article", paramvalidator.ReasonForbiddenChars, "error: article param %s is invalid", "Orca%23"), "article", paramvalidator.ReasonForbiddenChars},
+		{fmt.Errorf("wrapped: %w", paramvalidator.NewError("start", paramvalidator.ReasonBadDate, "error: start param is invalid")), "start", paramvalidator.ReasonBadDate},
+		{errors.New("error: boom"), "unknown", paramvalidator.ReasonInvalid},
+	}
+
+	for _, tc := range testCases {
+		before := metricstest.ValidationFailures(t, tc.expectedParam, tc.expectedReason)
+		e := Validation(tc.err)
+		after := metricstest.ValidationFailures(t, tc.expectedParam, tc.expectedReason)
+
+		if e.Status != http.StatusBadRequest || e.Message != tc.err.Error() || after-before != 1 {
+			t.Errorf("Validation(%v) returns %d %q counting %v failures for %s, %s; Expected 400 %q counting 1", tc.err, e.Status, e.Message, after-before, tc.expectedParam, tc.expectedReason, tc.err.Error())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"wikiviews/internal/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPErrorHandler renders every error returned by a handler or middleware in the error model and logs it,
+// so handlers can simply return an *Error
+func HTTPErrorHandler(err error, c echo.Context) {
+	logger := logging.FromContext(c.Request().Context())
+	if c.Response().Committed {
+		logger.Error("error after response was sent", "error", err)
+		return
+	}
+
+	var e *Error
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		e = fromHTTPError(httpErr)
+	} else {
+		e = From(err)
+	}
+
+	// A request cancelled by the server shutting down was not closed by the client
+	if e.Code == CodeClientClosed && errors.Is(context.Cause(c.Request().Context()), ErrShuttingDown) {
+		e = From(ErrShuttingDown)
+	}
+
+	// Server and upstream failures need attention, while the rest are the client's to fix
+	level := slog.LevelInfo
+	if e.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logger.Log(c.Request().Context(), level, "request failed", "error", err, "status", e.Status, "code", e.Code)
+
+	// Copy so shared errors are never mutated with a request id
+	body := *e
+	body.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(body.Status)
+	} else {
+		err = c.JSON(body.Status, body)
+	}
+	if err != nil {
+		logger.Error("error writing error response", "error", err)
+	}
+}
+
+// Middleware renders any error returned by the middleware and handlers after it with HTTPErrorHandler, so that
+// middleware before it, e.g. for logs, metrics and traces, sees the status the request was served with.
+// Register it after those, and before any middleware that can fail a request, e.g. the rate limiter
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := next(c); err != nil {
+				HTTPErrorHandler(err, c)
+			}
+			return nil
+		}
+	}
+}
+
+// Map errors raised by echo itself, e.g. unknown routes, bind failures and the rate limiter
+func fromHTTPError(err *echo.HTTPError) *Error {
+	message := http.StatusText(err.Code)
+	if m, ok := err.Message.(string); ok {
+		message = m
+	}
+
+	code := strings.ReplaceAll(strings.ToLower(http.StatusText(err.Code)), " ", "_")
+	switch err.Code {
+	case http.StatusBadRequest:
+		code = CodeInvalidParam
+	case http.StatusTooManyRequests:
+		code = CodeRateLimited
+	}
+
+	return New(err.Code, code, "error: "+strings.ToLower(message))
+}
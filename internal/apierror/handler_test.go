@@ -0,0 +1,56 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	testCases := []struct {
+		err    error
+		status int
+		body   Error
+	}{
+		{Validation(errors.New("error: date param is invalid")), http.StatusBadRequest, Error{Code: CodeInvalidParam, Message: "error: date param is invalid", RequestID: "abc"}},
+		{echo.ErrNotFound, http.StatusNotFound, Error{Code: CodeNotFound, Message: "error: not found", RequestID: "abc"}},
+		{echo.ErrTooManyRequests, http.StatusTooManyRequests, Error{Code: CodeRateLimited, Message: "error: too many requests", RequestID: "abc"}},
+		{errors.New("boom"), http.StatusInternalServerError, Error{Code: CodeInternal, Message: "error: internal server error", RequestID: "abc"}},
+	}
+
+	for _, tc := range testCases {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/pageviews", nil), rec)
+		c.Response().Header().Set(echo.HeaderXRequestID, "abc")
+
+		HTTPErrorHandler(tc.err, c)
+
+		var body Error
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if rec.Code != tc.status || !reflect.DeepEqual(body, tc.body) {
+			t.Errorf("HTTPErrorHandler(%v) responds %d %+v; Expected %d %+v", tc.err, rec.Code, body, tc.status, tc.body)
+		}
+	}
+}
+
+func TestHTTPErrorHandler_ShuttingDown(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrShuttingDown)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/pageviews", nil).WithContext(ctx)
+	HTTPErrorHandler(From(ctx.Err()), echo.New().NewContext(req, rec))
+
+	var body Error
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusServiceUnavailable || body.Code != CodeShuttingDown {
+		t.Errorf("HTTPErrorHandler for a request cancelled by shutdown responds %d %+v; Expected %d %s", rec.Code, body, http.StatusServiceUnavailable, CodeShuttingDown)
+	}
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/config"
+	"wikiviews/internal/logging"
+	"wikiviews/internal/metrics"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+type (
+	// Authenticator checks the API key of every request against the configured keys, enforcing the rate limit
+	// and daily quota of each. It is safe for concurrent use
+	Authenticator struct {
+		// Keys by the hex SHA-256 hash of their secret
+		keys    map[string]*key
+		counter Counter
+		now     func() time.Time
+	}
+
+	key struct {
+		config.APIKey
+		limiter *rate.Limiter
+	}
+)
+
+const (
+	// HeaderAPIKey carries an API key, as an alternative to an Authorization: Bearer header
+	HeaderAPIKey = "X-API-Key"
+	// Quota headers sent with every response to a key with a daily quota
+	HeaderQuotaLimit     = "X-Quota-Limit"
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+	// HeaderQuotaReset is the number of seconds until the quota resets, at midnight UTC
+	HeaderQuotaReset = "X-Quota-Reset"
+
+	// Context keys of the key a request presented, nil if it matched none, and of the name of the key it
+	// authenticated with
+	keyKey     = "api_key_config"
+	keyNameKey = "api_key"
+)
+
+// Enabled reports whether any keys are configured. Without any, every request is let through
+func (a *Authenticator) Enabled() bool {
+	return len(a.keys) > 0
+}
+
+// KeyName returns the name of the API key a request authenticated with, or "" if it did not authenticate
+func KeyName(c echo.Context) string {
+	name, _ := c.Get(keyNameKey).(string)
+	return name
+}
+
+// Identify looks up the API key a request presents, so KeyName reports it to middleware that runs before
+// Middleware, e.g. to exempt authenticated requests from the per IP rate limit. It never rejects a request:
+// requests without a valid, enabled key stay anonymous until Middleware refuses them
+func (a *Authenticator) Identify() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if a.Enabled() {
+				a.identify(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+// Middleware refuses requests without a valid API key with a 401, and with a disabled one with a 403,
+// then enforces the key's rate limit and daily quota with a 429. Routes in public, e.g. probes, are served
+// without a key. It does nothing unless the Authenticator is enabled
+func (a *Authenticator) Middleware(public ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !a.Enabled() || slices.Contains(public, c.Path()) {
+				return next(c)
+			}
+
+			if len(presentedKey(c.Request())) == 0 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="wikiviews"`)
+				return apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "error: an API key is required. Send it in an Authorization: Bearer or X-API-Key header")
+			}
+
+			k := a.identify(c)
+			if k == nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="wikiviews", error="invalid_token"`)
+				return apierror.New(http.StatusUnauthorized, apierror.CodeUnauthorized, "error: API key is invalid")
+			}
+
+			if k.Disabled {
+				return apierror.New(http.StatusForbidden, apierror.CodeForbidden, fmt.Sprintf("error: API key %s is disabled", k.Name))
+			}
+
+			// Tell the client when the key's next request will be allowed, without holding this one until then
+			if reservation := k.limiter.Reserve(); reservation.Delay() > 0 {
+				delay := reservation.Delay()
+				reservation.Cancel()
+				metrics.RateLimited()
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				return apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited, fmt.Sprintf("error: rate limit exceeded for API key %s", k.Name))
+			}
+
+			if err := a.useQuota(c, k); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Look up the key a request presents, once per request, returning nil if it presents none or one that matches
+// no configured key. A request authenticates only with an enabled key, which tags everything logged for it
+func (a *Authenticator) identify(c echo.Context) *key {
+	if k, ok := c.Get(keyKey).(*key); ok {
+		return k
+	}
+
+	var k *key
+	if secret := presentedKey(c.Request()); len(secret) > 0 {
+		sum := sha256.Sum256([]byte(secret))
+		k = a.keys[hex.EncodeToString(sum[:])]
+	}
+	c.Set(keyKey, k)
+
+	if k != nil && !k.Disabled {
+		c.Set(keyNameKey, k.Name)
+		req := c.Request()
+		c.SetRequest(req.WithContext(logging.WithLogger(req.Context(), logging.FromContext(req.Context()).With("api_key", k.Name))))
+	}
+
+	return k
+}
+
+// Count a request against the daily quota of k, if it has one, telling the client how much is left.
+// A failing counter is logged and bypassed, as the cache is, rather than failing every request
+func (a *Authenticator) useQuota(c echo.Context, k *key) error {
+	if k.DailyQuota == 0 {
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	now := a.now().UTC()
+	count, err := a.counter.Incr(ctx, k.Name, now.Format("20060102"))
+	if err != nil {
+		logging.FromContext(ctx).Warn("error counting quota", "error", err)
+		return nil
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	reset := strconv.Itoa(int(math.Ceil(midnight.Sub(now).Seconds())))
+
+	header := c.Response().Header()
+	header.Set(HeaderQuotaLimit, strconv.FormatInt(k.DailyQuota, 10))
+	header.Set(HeaderQuotaRemaining, strconv.FormatInt(max(0, k.DailyQuota-count), 10))
+	header.Set(HeaderQuotaReset, reset)
+
+	if count > k.DailyQuota {
+		metrics.QuotaExceeded(k.Name)
+		header.Set(echo.HeaderRetryAfter, reset)
+		return apierror.New(http.StatusTooManyRequests, apierror.CodeQuotaExceeded, fmt.Sprintf("error: daily quota of %d requests for API key %s is used up. It resets at midnight UTC", k.DailyQuota, k.Name))
+	}
+
+	return nil
+}
+
+// Return the API key sent with a request, from an Authorization: Bearer header or else an X-API-Key header.
+// Keys are never read from the query string, which is logged
+func presentedKey(req *http.Request) string {
+	scheme, token, ok := strings.Cut(req.Header.Get(echo.HeaderAuthorization), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return req.Header.Get(HeaderAPIKey)
+}
+
+// NewAuthenticator accepts keys, which must have passed config validation. Keys without a rate limit of their
+// own get defaultRateLimit requests per second, and daily quotas are counted with counter
+func NewAuthenticator(keys []config.APIKey, defaultRateLimit float64, counter Counter) *Authenticator {
+	a := &Authenticator{
+		keys:    make(map[string]*key, len(keys)),
+		counter: counter,
+		now:     time.Now,
+	}
+
+	for _, k := range keys {
+		limit := k.RateLimit
+		if limit == 0 {
+			limit = defaultRateLimit
+		}
+		// Allow a burst of a second's worth of requests, and always at least one
+		a.keys[k.SHA256] = &key{APIKey: k, limiter: rate.NewLimiter(rate.Limit(limit), max(1, int(math.Ceil(limit))))}
+	}
+
+	return a
+}
@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/config"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Serve /pageviews and /healthcheck behind an Authenticator for keys, as of 2024-03-10 23:00 UTC
+func newTestServer(keys ...config.APIKey) *echo.Echo {
+	a := NewAuthenticator(keys, 100, NewMemoryCounter())
+	a.now = func() time.Time { return time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC) }
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	e.Use(a.Middleware("/healthcheck"))
+	e.GET("/pageviews", func(c echo.Context) error { return c.String(http.StatusOK, KeyName(c)) })
+	e.GET("/healthcheck", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+
+	return e
+}
+
+func serve(e *echo.Echo, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header = header
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	e := newTestServer(
+		config.APIKey{Name: "dashboard", SHA256: hash("s3cret")},
+		config.APIKey{Name: "revoked", SHA256: hash("old"), Disabled: true},
+	)
+
+	testCases := []struct {
+		path           string
+		header         http.Header
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"/pageviews", http.Header{}, http.StatusUnauthorized, apierror.CodeUnauthorized},
+		{"/pageviews", http.Header{"Authorization": {"Bearer wrong"}}, http.StatusUnauthorized, apierror.CodeUnauthorized},
+		{"/pageviews", http.Header{"Authorization": {"Basic s3cret"}}, http.StatusUnauthorized, apierror.CodeUnauthorized},
+		{"/pageviews", http.Header{"Authorization": {"Bearer old"}}, http.StatusForbidden, apierror.CodeForbidden},
+		{"/pageviews", http.Header{"Authorization": {"bearer s3cret"}}, http.StatusOK, ""},
+		{"/pageviews", http.Header{"X-Api-Key": {"s3cret"}}, http.StatusOK, ""},
+		{"/healthcheck", http.Header{}, http.StatusOK, ""},
+	}
+
+	for _, tc := range testCases {
+		rec := serve(e, tc.path, tc.header)
+
+		var body apierror.Error
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if rec.Code != tc.expectedStatus || body.Code != tc.expectedCode {
+			t.Errorf("Middleware for %s with header %v responds %d %q; Expected %d %q", tc.path, tc.header, rec.Code, body.Code, tc.expectedStatus, tc.expectedCode)
+		}
+	}
+}
+
+func TestAuthenticator_Middleware_Disabled(t *testing.T) {
+	e := newTestServer()
+
+	if rec := serve(e, "/pageviews", http.Header{}); rec.Code != http.StatusOK {
+		t.Errorf("Middleware without keys responds %d; Expected %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestAuthenticator_Middleware_Quota(t *testing.T) {
+	e := newTestServer(config.APIKey{Name: "dashboard", SHA256: hash("s3cret"), DailyQuota: 2})
+	header := http.Header{"Authorization": {"Bearer s3cret"}}
+
+	testCases := []struct {
+		expectedStatus    int
+		expectedRemaining string
+	}{
+		{http.StatusOK, "1"},
+		{http.StatusOK, "0"},
+		{http.StatusTooManyRequests, "0"},
+	}
+
+	for i, tc := range testCases {
+		rec := serve(e, "/pageviews", header)
+
+		remaining := rec.Header().Get(HeaderQuotaRemaining)
+		if rec.Code != tc.expectedStatus || remaining != tc.expectedRemaining {
+			t.Errorf("Middleware for request %d responds %d with %s %q; Expected %d %q", i+1, rec.Code, HeaderQuotaRemaining, remaining, tc.expectedStatus, tc.expectedRemaining)
+		}
+
+		// An hour before midnight UTC
+		if reset := rec.Header().Get(HeaderQuotaReset); reset != "3600" {
+			t.Errorf("Middleware for request %d responds with %s %q; Expected %q", i+1, HeaderQuotaReset, reset, "3600")
+		}
+	}
+
+	rec := serve(e, "/pageviews", header)
+	var body apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body.Code != apierror.CodeQuotaExceeded || rec.Header().Get(echo.HeaderRetryAfter) != "3600" {
+		t.Errorf("Middleware over quota responds %q with Retry-After %q; Expected %q, %q", body.Code, rec.Header().Get(echo.HeaderRetryAfter), apierror.CodeQuotaExceeded, "3600")
+	}
+}
+
+func TestAuthenticator_Middleware_RateLimit(t *testing.T) {
+	e := newTestServer(config.APIKey{Name: "dashboard", SHA256: hash("s3cret"), RateLimit: 1})
+	header := http.Header{"Authorization": {"Bearer s3cret"}}
+
+	serve(e, "/pageviews", header)
+	rec := serve(e, "/pageviews", header)
+
+	var body apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusTooManyRequests || body.Code != apierror.CodeRateLimited {
+		t.Errorf("Middleware over the key's rate limit responds %d %q; Expected %d %q", rec.Code, body.Code, http.StatusTooManyRequests, apierror.CodeRateLimited)
+	}
+	if retryAfter := rec.Header().Get(echo.HeaderRetryAfter); retryAfter != "1" {
+		t.Errorf("Middleware over the key's rate limit responds with Retry-After %q; Expected %q", retryAfter, "1")
+	}
+}
+
+func TestAuthenticator_Identify(t *testing.T) {
+	a := NewAuthenticator([]config.APIKey{
+		{Name: "dashboard", SHA256: hash("s3cret")},
+		{Name: "revoked", SHA256: hash("old"), Disabled: true},
+	}, 100, NewMemoryCounter())
+
+	// Limit anonymous requests to one per IP address between Identify and Middleware, as the server does
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	e.Use(a.Identify())
+	e.Use(middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: func(c echo.Context) bool { return len(KeyName(c)) > 0 },
+		Store:   middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{Rate: 1, Burst: 1}),
+	}))
+	e.Use(a.Middleware())
+	e.GET("/pageviews", func(c echo.Context) error { return c.String(http.StatusOK, KeyName(c)) })
+
+	testCases := []struct {
+		header         http.Header
+		expectedStatus int
+	}{
+		{http.Header{"Authorization": {"Bearer wrong"}}, http.StatusUnauthorized},
+		// Guessing again is rate limited rather than refused
+		{http.Header{"Authorization": {"Bearer guess"}}, http.StatusTooManyRequests},
+		{http.Header{"Authorization": {"Bearer old"}}, http.StatusTooManyRequests},
+		{http.Header{}, http.StatusTooManyRequests},
+		// A valid key is limited by its own rate instead
+		{http.Header{"Authorization": {"Bearer s3cret"}}, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		rec := serve(e, "/pageviews", tc.header)
+
+		if rec.Code != tc.expectedStatus {
+			t.Errorf("Identify for header %v responds %d; Expected %d", tc.header, rec.Code, tc.expectedStatus)
+		}
+	}
+}
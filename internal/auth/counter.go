@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Counter counts the requests of each API key per day against its quota. A backend must be safe for concurrent use
+type Counter interface {
+	// Incr adds a request by key on day, e.g. 20240310, returning the count for that day so far
+	Incr(ctx context.Context, key, day string) (count int64, err error)
+}
+
+type (
+	// MemoryCounter counts in process, so each replica counts its own share of a key's requests
+	MemoryCounter struct {
+		mu     sync.Mutex
+		counts map[string]dayCount
+	}
+
+	dayCount struct {
+		day   string
+		count int64
+	}
+)
+
+func (mc *MemoryCounter) Incr(ctx context.Context, key, day string) (int64, error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	// Only today's count is kept per key, so counts never grow past one per key
+	dc := mc.counts[key]
+	if dc.day != day {
+		dc = dayCount{day: day}
+	}
+	dc.count++
+	mc.counts[key] = dc
+
+	return dc.count, nil
+}
+
+func NewMemoryCounter() *MemoryCounter {
+	return &MemoryCounter{counts: make(map[string]dayCount)}
+}
+
+// RedisCounter counts in Redis, so quotas hold across replicas and restarts
+type RedisCounter struct {
+	client *redis.Client
+	prefix string
+}
+
+// How long a day's count is kept in Redis, long enough to outlast the day in any time zone
+const redisCountTTL = 48 * time.Hour
+
+func (rc *RedisCounter) Incr(ctx context.Context, key, day string) (int64, error) {
+	k := rc.prefix + key + ":" + day
+
+	pipe := rc.client.TxPipeline()
+	incr := pipe.Incr(ctx, k)
+	pipe.Expire(ctx, k, redisCountTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+// NewRedisCounter connects to the Redis server at addr, e.g. localhost:6379
+func NewRedisCounter(addr string) *RedisCounter {
+	return &RedisCounter{
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+		prefix: "wikiviews:quota:",
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestCounters_Incr(t *testing.T) {
+	server := miniredis.RunT(t)
+	counters := map[string]Counter{
+		"MemoryCounter": NewMemoryCounter(),
+		"RedisCounter":  NewRedisCounter(server.Addr()),
+	}
+
+	testCases := []struct {
+		key           string
+		day           string
+		expectedCount int64
+	}{
+		{"dashboard", "20240310", 1},
+		{"dashboard", "20240310", 2},
+		{"notebook", "20240310", 1},
+		{"dashboard", "20240311", 1},
+	}
+
+	for name, counter := range counters {
+		for _, tc := range testCases {
+			count, err := counter.Incr(context.Background(), tc.key, tc.day)
+
+			if count != tc.expectedCount || err != nil {
+				t.Errorf("%s.Incr(%q, %q) returns %d, err = %v; Expected %d, nil", name, tc.key, tc.day, count, err, tc.expectedCount)
+			}
+		}
+	}
+
+	if ttl := server.TTL("wikiviews:quota:dashboard:20240310"); ttl != redisCountTTL {
+		t.Errorf("RedisCounter.Incr sets ttl %s; Expected %s", ttl, redisCountTTL)
+	}
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"wikiviews/internal/logging"
+)
+
+// Cache stores upstream responses by key. A backend must be safe for concurrent use
+type Cache interface {
+	// Get returns the value stored for key, and false if there is none or it has expired
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores value for key until ttl has passed
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Ping returns an error if the backend cannot be reached
+	Ping(ctx context.Context) error
+}
+
+const (
+	// How long to keep data for periods that have closed. Pageviews for a past month never change
+	ClosedPeriodTTL = 30 * 24 * time.Hour
+	// How long to keep data for periods that are still open, e.g. the current month
+	OpenPeriodTTL = 5 * time.Minute
+	// How long after a period ends before the Wikipedia API has finished loading its data
+	settleDelay = 48 * time.Hour
+)
+
+// TTL returns how long to cache data for a period ending at periodEnd
+func TTL(periodEnd, now time.Time) time.Duration {
+	if periodEnd.Add(settleDelay).Before(now) {
+		return ClosedPeriodTTL
+	}
+
+	return OpenPeriodTTL
+}
+
+// Fetch returns the value cached as JSON under key, or else the value returned by fetch, caching it for ttl.
+// hit reports whether the value was served from the cache. A failing cache is logged and bypassed rather than
+// failing the request
+func Fetch[T any](ctx context.Context, c Cache, key string, ttl time.Duration, fetch func() (T, error)) (value T, hit bool, err error) {
+	logger := logging.FromContext(ctx)
+
+	cached, ok, cacheErr := c.Get(ctx, key)
+	if cacheErr != nil {
+		logger.Warn("error reading cache", "error", cacheErr)
+	} else if ok {
+		if cacheErr = json.Unmarshal(cached, &value); cacheErr == nil {
+			return value, true, nil
+		}
+		logger.Warn("error unmarshalling cached JSON", "error", cacheErr)
+	}
+
+	if value, err = fetch(); err != nil {
+		return
+	}
+
+	if cached, cacheErr = json.Marshal(value); cacheErr == nil {
+		cacheErr = c.Set(ctx, key, cached, ttl)
+	}
+	if cacheErr != nil {
+		logger.Warn("error writing cache", "error", cacheErr)
+	}
+
+	return
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTTL(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		periodEnd   time.Time
+		expectedTTL time.Duration
+	}{
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), ClosedPeriodTTL},
+		{time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC), ClosedPeriodTTL},
+		{time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), OpenPeriodTTL},
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), OpenPeriodTTL},
+	}
+
+	for _, tc := range testCases {
+		actualTTL := TTL(tc.periodEnd, now)
+
+		if actualTTL != tc.expectedTTL {
+			t.Errorf("TTL(%s) returns %s; Expected %s", tc.periodEnd, actualTTL, tc.expectedTTL)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	c := NewMemoryCache(10)
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"Orca"}, nil
+	}
+
+	testCases := []struct {
+		expectedHit   bool
+		expectedCalls int
+	}{
+		{false, 1},
+		{true, 1},
+	}
+
+	for i, tc := range testCases {
+		value, hit, err := Fetch(context.Background(), c, "key", time.Minute, fetch)
+
+		if err != nil || len(value) != 1 || value[0] != "Orca" || hit != tc.expectedHit || calls != tc.expectedCalls {
+			t.Errorf("Fetch %d returns %q, hit = %t, err = %v after %d fetches; Expected [Orca], hit = %t after %d", i+1, value, hit, err, calls, tc.expectedHit, tc.expectedCalls)
+		}
+	}
+}
+
+func TestFetch_Error(t *testing.T) {
+	c := NewMemoryCache(10)
+	fetchErr := errors.New("boom")
+
+	if _, _, err := Fetch(context.Background(), c, "key", time.Minute, func() ([]string, error) { return nil, fetchErr }); err != fetchErr {
+		t.Errorf("Fetch returns err = %v; Expected %v", err, fetchErr)
+	}
+
+	// Failed fetches are not cached
+	if _, ok, _ := c.Get(context.Background(), "key"); ok {
+		t.Errorf("Fetch caches the result of a failed fetch")
+	}
+}
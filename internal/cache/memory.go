@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// MemoryCache is an in-process LRU cache, evicting the least recently used entry once full
+	MemoryCache struct {
+		mu       sync.Mutex
+		capacity int
+		entries  map[string]*list.Element
+		order    *list.List
+		now      func() time.Time
+	}
+
+	memoryEntry struct {
+		key       string
+		value     []byte
+		expiresAt time.Time
+	}
+)
+
+func (mc *MemoryCache) Get(ctx context.Context, key string) (value []byte, ok bool, err error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	el, ok := mc.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := el.Value.(*memoryEntry)
+	if !mc.now().Before(entry.expiresAt) {
+		mc.remove(el)
+		return nil, false, nil
+	}
+
+	mc.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+func (mc *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	expiresAt := mc.now().Add(ttl)
+	if el, ok := mc.entries[key]; ok {
+		entry := el.Value.(*memoryEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		mc.order.MoveToFront(el)
+		return nil
+	}
+
+	mc.entries[key] = mc.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	if mc.order.Len() > mc.capacity {
+		mc.remove(mc.order.Back())
+	}
+
+	return nil
+}
+
+// Ping always succeeds, as the cache lives in process
+func (mc *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (mc *MemoryCache) remove(el *list.Element) {
+	mc.order.Remove(el)
+	delete(mc.entries, el.Value.(*memoryEntry).key)
+}
+
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
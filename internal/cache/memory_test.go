@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	mc := NewMemoryCache(2)
+
+	if _, ok, _ := mc.Get(ctx, "Orca"); ok {
+		t.Errorf("MemoryCache.Get(%q) on empty cache returns ok = true; Expected false", "Orca")
+	}
+
+	mc.Set(ctx, "Orca", []byte("8420"), time.Minute)
+	value, ok, _ := mc.Get(ctx, "Orca")
+	if !ok || string(value) != "8420" {
+		t.Errorf("MemoryCache.Get(%q) returns %q, ok = %t; Expected %q, true", "Orca", value, ok, "8420")
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	mc := NewMemoryCache(2)
+	mc.now = func() time.Time { return now }
+
+	mc.Set(ctx, "Orca", []byte("8420"), time.Minute)
+
+	now = now.Add(59 * time.Second)
+	if _, ok, _ := mc.Get(ctx, "Orca"); !ok {
+		t.Errorf("MemoryCache.Get(%q) before expiry returns ok = false; Expected true", "Orca")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok, _ := mc.Get(ctx, "Orca"); ok {
+		t.Errorf("MemoryCache.Get(%q) after expiry returns ok = true; Expected false", "Orca")
+	}
+}
+
+func TestMemoryCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	mc := NewMemoryCache(2)
+
+	mc.Set(ctx, "Orca", []byte("1"), time.Minute)
+	mc.Set(ctx, "Man_page", []byte("2"), time.Minute)
+	// Touch Orca so Man_page becomes the least recently used entry
+	mc.Get(ctx, "Orca")
+	mc.Set(ctx, "Michael_Phelps", []byte("3"), time.Minute)
+
+	testCases := []struct {
+		key string
+		ok  bool
+	}{
+		{"Orca", true},
+		{"Man_page", false},
+		{"Michael_Phelps", true},
+	}
+
+	for _, tc := range testCases {
+		_, ok, _ := mc.Get(ctx, tc.key)
+
+		if ok != tc.ok {
+			t.Errorf("MemoryCache.Get(%q) returns ok = %t; Expected %t", tc.key, ok, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache stores entries in Redis, so they are shared across replicas and survive restarts
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+func (rc *RedisCache) Get(ctx context.Context, key string) (value []byte, ok bool, err error) {
+	value, err = rc.client.Get(ctx, rc.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (rc *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return rc.client.Set(ctx, rc.prefix+key, value, ttl).Err()
+}
+
+func (rc *RedisCache) Ping(ctx context.Context) error {
+	return rc.client.Ping(ctx).Err()
+}
+
+// NewRedisCache connects to the Redis server at addr, e.g. localhost:6379
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+		prefix: "wikiviews:",
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedisCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	rc := NewRedisCache(server.Addr())
+
+	if _, ok, err := rc.Get(ctx, "Orca"); ok || err != nil {
+		t.Errorf("RedisCache.Get(%q) on empty cache returns ok = %t, err = %v; Expected false, nil", "Orca", ok, err)
+	}
+
+	if err := rc.Set(ctx, "Orca", []byte("8420"), time.Minute); err != nil {
+		t.Fatalf("RedisCache.Set(%q) returns err = %v", "Orca", err)
+	}
+
+	value, ok, err := rc.Get(ctx, "Orca")
+	if !ok || err != nil || string(value) != "8420" {
+		t.Errorf("RedisCache.Get(%q) returns %q, ok = %t, err = %v; Expected %q, true, nil", "Orca", value, ok, err, "8420")
+	}
+
+	if !server.Exists("wikiviews:Orca") {
+		t.Errorf("RedisCache.Set(%q) did not store key %q", "Orca", "wikiviews:Orca")
+	}
+}
+
+func TestRedisCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	rc := NewRedisCache(server.Addr())
+
+	rc.Set(ctx, "Orca", []byte("8420"), time.Minute)
+	server.FastForward(time.Minute)
+
+	if _, ok, _ := rc.Get(ctx, "Orca"); ok {
+		t.Errorf("RedisCache.Get(%q) after expiry returns ok = true; Expected false", "Orca")
+	}
+}
+
+func TestRedisCache_Unavailable(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	rc := NewRedisCache(server.Addr())
+	server.Close()
+
+	if _, _, err := rc.Get(ctx, "Orca"); err == nil {
+		t.Errorf("RedisCache.Get(%q) with server down returns err = nil; Expected an error", "Orca")
+	}
+
+	if err := rc.Ping(ctx); err == nil {
+		t.Errorf("RedisCache.Ping with server down returns err = nil; Expected an error")
+	}
+}
@@ -0,0 +1,335 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"wikiviews/internal/wikimedia"
+)
+
+type (
+	// Config holds every setting of the HTTP server. Start from Default, or Load a file with environment overrides
+	Config struct {
+		// Addr is the address the server listens on, e.g. :8080
+		Addr string `json:"addr"`
+		// RateLimit is the most requests per second the server accepts from one client
+		RateLimit float64 `json:"rate_limit"`
+		// DrainDelay is how long the server keeps serving after a shutdown signal, failing /healthcheck,
+		// so load balancers can stop sending it requests before it stops accepting them
+		DrainDelay Duration `json:"drain_delay"`
+		// ShutdownTimeout bounds how long in-flight requests may take to finish once the server stops accepting
+		// new ones. Any still running are then cancelled, along with their upstream calls
+		ShutdownTimeout Duration `json:"shutdown_timeout"`
+		// LogLevel is the least severe level logged, one of debug, info, warn or error
+		LogLevel string   `json:"log_level"`
+		Upstream Upstream `json:"upstream"`
+		Cache    Cache    `json:"cache"`
+		Tracing  Tracing  `json:"tracing"`
+		Auth     Auth     `json:"auth"`
+	}
+
+	// Upstream holds settings for calls to the Wikimedia APIs
+	Upstream struct {
+		// BaseUrl is the metrics API, e.g. a mock upstream in staging
+		BaseUrl string `json:"base_url"`
+		// ProjectUrl is the API of each project's own wiki, with {project} replaced by the project domain
+		ProjectUrl string `json:"project_url"`
+		// UserAgent identifies the service to Wikimedia, whose policy asks for contact details,
+		// e.g. WikiViews/1.0 (https://example.org/wikiviews; ops@example.org)
+		UserAgent string `json:"user_agent"`
+		// Timeout bounds a single attempt at an upstream call
+		Timeout             Duration `json:"timeout"`
+		DialTimeout         Duration `json:"dial_timeout"`
+		TLSHandshakeTimeout Duration `json:"tls_handshake_timeout"`
+		// ResponseHeaderTimeout bounds the wait for upstream to start responding
+		ResponseHeaderTimeout Duration `json:"response_header_timeout"`
+		IdleConnTimeout       Duration `json:"idle_conn_timeout"`
+		// MaxIdleConns bounds idle connections kept across every host, i.e. the metrics API and each project's wiki
+		MaxIdleConns        int `json:"max_idle_conns"`
+		MaxIdleConnsPerHost int `json:"max_idle_conns_per_host"`
+	}
+
+	// Cache holds settings for the response cache
+	Cache struct {
+		// Capacity is the most responses the in-memory cache holds before evicting the least recently used
+		Capacity int `json:"capacity"`
+		// RedisAddr switches to a Redis cache at that address when set
+		RedisAddr string `json:"redis_addr"`
+	}
+
+	// Tracing holds settings for OpenTelemetry tracing
+	Tracing struct {
+		// Endpoint is the URL of an OTLP/HTTP collector to export spans to, e.g. http://otel-collector:4318.
+		// Tracing is off when it is empty
+		Endpoint string `json:"endpoint"`
+		// SampleRatio is the fraction of traces started here that are recorded. Requests arriving with a
+		// sampled parent trace are always recorded
+		SampleRatio float64 `json:"sample_ratio"`
+	}
+
+	// Auth holds the API keys clients authenticate with. Auth is off, and clients are rate limited by IP
+	// address, when there are none
+	Auth struct {
+		Keys []APIKey `json:"keys"`
+		// KeysFile is a JSON file holding a list of more keys, e.g. a mounted secret, read on startup
+		KeysFile string `json:"keys_file"`
+	}
+
+	// APIKey is a key a client may authenticate with, and the limits on its use
+	APIKey struct {
+		// Name identifies the client in logs, metrics and errors
+		Name string `json:"name"`
+		// SHA256 is the hex SHA-256 hash of the key, so keys are never stored in the clear
+		SHA256 string `json:"sha256"`
+		// RateLimit is the most requests per second the key may make, or the server's rate_limit when 0
+		RateLimit float64 `json:"rate_limit"`
+		// DailyQuota is the most requests the key may make per UTC day, or no limit when 0
+		DailyQuota int64 `json:"daily_quota"`
+		// Disabled keys are refused with a 403, e.g. to revoke one while keeping its name on record
+		Disabled bool `json:"disabled"`
+	}
+
+	// Duration is a time.Duration written in a config file as a string, e.g. "10s"
+	Duration time.Duration
+)
+
+// Default returns the settings used for anything a config file or the environment does not set
+func Default() *Config {
+	return &Config{
+		Addr:            ":8080",
+		RateLimit:       20,
+		DrainDelay:      Duration(5 * time.Second),
+		ShutdownTimeout: Duration(30 * time.Second),
+		LogLevel:        "info",
+		Upstream: Upstream{
+			BaseUrl:               wikimedia.DefaultBaseUrl,
+			ProjectUrl:            wikimedia.DefaultProjectUrl,
+			UserAgent:             wikimedia.DefaultUserAgent,
+			Timeout:               Duration(wikimedia.DefaultTimeout),
+			DialTimeout:           Duration(5 * time.Second),
+			TLSHandshakeTimeout:   Duration(5 * time.Second),
+			ResponseHeaderTimeout: Duration(10 * time.Second),
+			IdleConnTimeout:       Duration(30 * time.Second),
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+		},
+		Cache: Cache{
+			Capacity: 10000,
+		},
+		Tracing: Tracing{
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load returns the default settings overlaid with the JSON config file at path, if any, and then with
+// environment variables. The result is validated, with every problem reported in one error
+func Load(path string) (*Config, error) {
+	c := Default()
+
+	if len(path) > 0 {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+
+		// Reject misspelled settings rather than silently ignoring them
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(c); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+
+	envErr := c.applyEnv(os.LookupEnv)
+	keysErr := c.Auth.loadKeysFile()
+
+	if err := errors.Join(envErr, keysErr, c.Validate()); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Add the keys listed in the keys file, if any, to those set in the config file
+func (a *Auth) loadKeysFile() error {
+	if len(a.KeysFile) == 0 {
+		return nil
+	}
+
+	b, err := os.ReadFile(a.KeysFile)
+	if err != nil {
+		return fmt.Errorf("error reading keys file: %w", err)
+	}
+
+	var keys []APIKey
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&keys); err != nil {
+		return fmt.Errorf("error parsing keys file %s: %w", a.KeysFile, err)
+	}
+
+	a.Keys = append(a.Keys, keys...)
+	return nil
+}
+
+// Validate checks every setting, joining the errors for all that are invalid
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(setting, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("error: config setting %s is invalid: %s", setting, fmt.Sprintf(format, args...)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		invalid("addr", "must be a host and port to listen on, e.g. :8080")
+	}
+
+	if c.RateLimit <= 0 {
+		invalid("rate_limit", "must be a positive number of requests per second")
+	}
+
+	urls := []struct {
+		setting string
+		value   string
+	}{
+		{"upstream.base_url", c.Upstream.BaseUrl},
+		{"upstream.project_url", strings.ReplaceAll(c.Upstream.ProjectUrl, "{project}", "en.wikipedia.org")},
+	}
+	for _, u := range urls {
+		if parsed, err := url.Parse(u.value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+			invalid(u.setting, "must be an absolute http or https URL")
+		}
+	}
+	if !strings.Contains(c.Upstream.ProjectUrl, "{project}") {
+		invalid("upstream.project_url", "must contain {project}, e.g. https://{project}/w")
+	}
+
+	if len(strings.TrimSpace(c.Upstream.UserAgent)) == 0 {
+		invalid("upstream.user_agent", "cannot be empty")
+	}
+
+	if _, err := c.Level(); err != nil {
+		invalid("log_level", "must be one of debug, info, warn, error")
+	}
+
+	if c.DrainDelay < 0 {
+		invalid("drain_delay", "cannot be negative")
+	}
+
+	durations := []struct {
+		setting string
+		value   Duration
+	}{
+		{"shutdown_timeout", c.ShutdownTimeout},
+		{"upstream.timeout", c.Upstream.Timeout},
+		{"upstream.dial_timeout", c.Upstream.DialTimeout},
+		{"upstream.tls_handshake_timeout", c.Upstream.TLSHandshakeTimeout},
+		{"upstream.response_header_timeout", c.Upstream.ResponseHeaderTimeout},
+		{"upstream.idle_conn_timeout", c.Upstream.IdleConnTimeout},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			invalid(d.setting, "must be a positive duration, e.g. 10s")
+		}
+	}
+
+	if c.Upstream.MaxIdleConnsPerHost <= 0 {
+		invalid("upstream.max_idle_conns_per_host", "must be a positive number")
+	}
+	if c.Upstream.MaxIdleConns < c.Upstream.MaxIdleConnsPerHost {
+		invalid("upstream.max_idle_conns", "must be at least upstream.max_idle_conns_per_host")
+	}
+
+	if c.Cache.Capacity <= 0 {
+		invalid("cache.capacity", "must be a positive number")
+	}
+
+	if len(c.Tracing.Endpoint) > 0 {
+		if parsed, err := url.Parse(c.Tracing.Endpoint); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+			invalid("tracing.endpoint", "must be an absolute http or https URL")
+		}
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		invalid("tracing.sample_ratio", "must be a number from 0 to 1")
+	}
+
+	names := make(map[string]bool)
+	hashes := make(map[string]bool)
+	for i, key := range c.Auth.Keys {
+		setting := fmt.Sprintf("auth.keys[%d]", i)
+		if len(key.Name) == 0 {
+			invalid(setting+".name", "cannot be empty")
+		} else if names[key.Name] {
+			invalid(setting+".name", "%s is already the name of another key", key.Name)
+		}
+		names[key.Name] = true
+
+		if !sha256Re.MatchString(key.SHA256) {
+			invalid(setting+".sha256", "must be the 64 lower case hex digits of a SHA-256 hash")
+		} else if hashes[key.SHA256] {
+			invalid(setting+".sha256", "is the hash of another key")
+		}
+		hashes[key.SHA256] = true
+
+		if key.RateLimit < 0 {
+			invalid(setting+".rate_limit", "cannot be negative")
+		}
+
+		if key.DailyQuota < 0 {
+			invalid(setting+".daily_quota", "cannot be negative")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// A hex SHA-256 hash, as written by sha256sum
+var sha256Re = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Level returns the log level named by LogLevel
+func (c *Config) Level() (level slog.Level, err error) {
+	err = level.UnmarshalText([]byte(c.LogLevel))
+	return
+}
+
+// ClientOptions returns the options of a Wikimedia API client for these settings
+func (u Upstream) ClientOptions() []wikimedia.Option {
+	return []wikimedia.Option{
+		wikimedia.WithProjectUrl(u.ProjectUrl),
+		wikimedia.WithUserAgent(u.UserAgent),
+		wikimedia.WithTimeout(time.Duration(u.Timeout)),
+	}
+}
+
+// HasContact reports whether the User-Agent gives contact details, a URL or an email address, as Wikimedia asks
+func (u Upstream) HasContact() bool {
+	return strings.Contains(u.UserAgent, "@") || strings.Contains(u.UserAgent, "http")
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string, e.g. \"10s\"")
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
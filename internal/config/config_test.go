@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Write a config file with contents to a temporary directory, returning its path
+func writeConfig(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad_Default(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load with no file returns err = %v; Expected nil", err)
+	}
+
+	if !reflect.DeepEqual(c, Default()) {
+		t.Errorf("Load with no file returns %+v; Expected the defaults", c)
+	}
+}
+
+func TestLoad_FileAndEnv(t *testing.T) {
+	path := writeConfig(t, `{
+		"addr": ":9090",
+		"rate_limit": 5,
+		"upstream": {"base_url": "http://mock-upstream:8000/metrics", "user_agent": "WikiViews/1.0 (ops@example.org)", "timeout": "3s"},
+		"cache": {"capacity": 50},
+		"auth": {"keys": [{"name": "dashboard", "sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "daily_quota": 1000}]}
+	}`)
+	keysFile := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(keysFile, []byte(`[{"name": "notebook", "sha256": "f3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "rate_limit": 2}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envAuthKeysFile, keysFile)
+	t.Setenv(envRateLimit, "7.5")
+	t.Setenv(envRedisAddr, "redis:6379")
+	t.Setenv(envLogLevel, "debug")
+	t.Setenv(envTracingEndpoint, "http://otel-collector:4318")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returns err = %v; Expected nil", err)
+	}
+
+	expected := Default()
+	expected.Addr = ":9090"
+	expected.RateLimit = 7.5
+	expected.Upstream.BaseUrl = "http://mock-upstream:8000/metrics"
+	expected.Upstream.UserAgent = "WikiViews/1.0 (ops@example.org)"
+	expected.Upstream.Timeout = Duration(3 * time.Second)
+	expected.Cache.Capacity = 50
+	expected.Cache.RedisAddr = "redis:6379"
+	expected.LogLevel = "debug"
+	expected.Tracing.Endpoint = "http://otel-collector:4318"
+	expected.Auth.KeysFile = keysFile
+	expected.Auth.Keys = []APIKey{
+		{Name: "dashboard", SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", DailyQuota: 1000},
+		{Name: "notebook", SHA256: "f3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", RateLimit: 2},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("Load returns %+v; Expected %+v", c, expected)
+	}
+
+	if !c.Upstream.HasContact() {
+		t.Errorf("Upstream.HasContact for user agent %q returns false; Expected true", c.Upstream.UserAgent)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	testCases := []struct {
+		file     string
+		env      map[string]string
+		expected []string
+	}{
+		{`{"adr": ":9090"}`, nil, []string{`unknown field "adr"`}},
+		{`{"upstream": {"timeout": 10}}`, nil, []string{`duration must be a string`}},
+		{`{}`, map[string]string{envCacheCapacity: "lots"}, []string{"environment variable WIKIVIEWS_CACHE_CAPACITY is invalid"}},
+		{`{"log_level": "verbose"}`, nil, []string{"config setting log_level is invalid"}},
+		{`{"upstream": {"max_idle_conns": 5}}`, nil, []string{"config setting upstream.max_idle_conns is invalid"}},
+		{
+			`{"tracing": {"endpoint": "otel-collector:4318", "sample_ratio": 2}}`, nil,
+			[]string{"config setting tracing.endpoint is invalid", "config setting tracing.sample_ratio is invalid"},
+		},
+		{
+			`{"auth": {"keys": [{"name": "dashboard", "sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}, {"name": "dashboard", "sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "daily_quota": -1}, {"sha256": "secret"}]}}`, nil,
+			[]string{
+				"config setting auth.keys[1].name is invalid",
+				"config setting auth.keys[1].sha256 is invalid: is the hash of another key",
+				"config setting auth.keys[1].daily_quota is invalid",
+				"config setting auth.keys[2].name is invalid: cannot be empty",
+				"config setting auth.keys[2].sha256 is invalid",
+			},
+		},
+		{
+			`{"log_level": "verbose", "auth": {"keys_file": "missing.json"}}`, map[string]string{envCacheCapacity: "lots"},
+			[]string{"environment variable WIKIVIEWS_CACHE_CAPACITY is invalid", "error reading keys file", "config setting log_level is invalid"},
+		},
+		{
+			`{"addr": "8080", "rate_limit": 0, "upstream": {"project_url": "https://wikipedia.org/w", "user_agent": " ", "timeout": "0s"}}`,
+			map[string]string{envBaseUrl: "wikimedia.org"},
+			[]string{
+				"config setting addr is invalid",
+				"config setting rate_limit is invalid",
+				"config setting upstream.base_url is invalid",
+				"config setting upstream.project_url is invalid: must contain {project}",
+				"config setting upstream.user_agent is invalid",
+				"config setting upstream.timeout is invalid",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load(writeConfig(t, tc.file))
+			if err == nil {
+				t.Fatalf("Load returns err = nil; Expected errors %q", tc.expected)
+			}
+
+			for _, expected := range tc.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Load returns err = %q; Expected it to contain %q", err, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Environment variables overriding each setting
+const (
+	EnvConfigFile = "WIKIVIEWS_CONFIG"
+
+	envAddr                  = "WIKIVIEWS_ADDR"
+	envRateLimit             = "WIKIVIEWS_RATE_LIMIT"
+	envDrainDelay            = "WIKIVIEWS_DRAIN_DELAY"
+	envShutdownTimeout       = "WIKIVIEWS_SHUTDOWN_TIMEOUT"
+	envLogLevel              = "WIKIVIEWS_LOG_LEVEL"
+	envBaseUrl               = "WIKIVIEWS_UPSTREAM_BASE_URL"
+	envProjectUrl            = "WIKIVIEWS_UPSTREAM_PROJECT_URL"
+	envUserAgent             = "WIKIVIEWS_USER_AGENT"
+	envTimeout               = "WIKIVIEWS_UPSTREAM_TIMEOUT"
+	envDialTimeout           = "WIKIVIEWS_UPSTREAM_DIAL_TIMEOUT"
+	envTLSHandshakeTimeout   = "WIKIVIEWS_UPSTREAM_TLS_HANDSHAKE_TIMEOUT"
+	envResponseHeaderTimeout = "WIKIVIEWS_UPSTREAM_RESPONSE_HEADER_TIMEOUT"
+	envIdleConnTimeout       = "WIKIVIEWS_UPSTREAM_IDLE_CONN_TIMEOUT"
+	envMaxIdleConns          = "WIKIVIEWS_UPSTREAM_MAX_IDLE_CONNS"
+	envMaxIdleConnsPerHost   = "WIKIVIEWS_UPSTREAM_MAX_IDLE_CONNS_PER_HOST"
+	envCacheCapacity         = "WIKIVIEWS_CACHE_CAPACITY"
+	envTracingEndpoint       = "WIKIVIEWS_TRACING_ENDPOINT"
+	envTracingSampleRatio    = "WIKIVIEWS_TRACING_SAMPLE_RATIO"
+	envAuthKeysFile          = "WIKIVIEWS_AUTH_KEYS_FILE"
+	// REDIS_ADDR predates the config package, so keeps its name
+	envRedisAddr = "REDIS_ADDR"
+)
+
+// Override settings with any environment variables set, as looked up by lookup, e.g. os.LookupEnv
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	str := func(name string, setting *string) {
+		if value, ok := lookup(name); ok {
+			*setting = value
+		}
+	}
+	integer := func(name string, setting *int) {
+		if value, ok := lookup(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error: environment variable %s is invalid: %s is not a whole number", name, value))
+				return
+			}
+			*setting = n
+		}
+	}
+	duration := func(name string, setting *Duration) {
+		if value, ok := lookup(name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error: environment variable %s is invalid: %s is not a duration, e.g. 10s", name, value))
+				return
+			}
+			*setting = Duration(d)
+		}
+	}
+
+	number := func(name string, setting *float64) {
+		if value, ok := lookup(name); ok {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error: environment variable %s is invalid: %s is not a number", name, value))
+				return
+			}
+			*setting = n
+		}
+	}
+
+	str(envAddr, &c.Addr)
+	number(envRateLimit, &c.RateLimit)
+
+	duration(envDrainDelay, &c.DrainDelay)
+	duration(envShutdownTimeout, &c.ShutdownTimeout)
+	str(envLogLevel, &c.LogLevel)
+
+	str(envBaseUrl, &c.Upstream.BaseUrl)
+	str(envProjectUrl, &c.Upstream.ProjectUrl)
+	str(envUserAgent, &c.Upstream.UserAgent)
+	duration(envTimeout, &c.Upstream.Timeout)
+	duration(envDialTimeout, &c.Upstream.DialTimeout)
+	duration(envTLSHandshakeTimeout, &c.Upstream.TLSHandshakeTimeout)
+	duration(envResponseHeaderTimeout, &c.Upstream.ResponseHeaderTimeout)
+	duration(envIdleConnTimeout, &c.Upstream.IdleConnTimeout)
+	integer(envMaxIdleConns, &c.Upstream.MaxIdleConns)
+	integer(envMaxIdleConnsPerHost, &c.Upstream.MaxIdleConnsPerHost)
+
+	integer(envCacheCapacity, &c.Cache.Capacity)
+	str(envRedisAddr, &c.Cache.RedisAddr)
+
+	str(envTracingEndpoint, &c.Tracing.Endpoint)
+	number(envTracingSampleRatio, &c.Tracing.SampleRatio)
+
+	str(envAuthKeysFile, &c.Auth.KeysFile)
+
+	return errors.Join(errs...)
+}
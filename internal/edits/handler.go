@@ -0,0 +1,133 @@
+package edits
+
+import (
+	"strings"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/paramformatter"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Item is one data point of an edits series, shaped like a /pageviews item
+	Item struct {
+		Project     string `json:"project"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		EditorType  string `json:"editor_type"`
+		PageType    string `json:"page_type"`
+		Edits       int64  `json:"edits"`
+	}
+
+	Params struct {
+		Project     string `query:"project"`
+		EditorType  string `query:"editor_type"`
+		PageType    string `query:"page_type"`
+		Granularity string `query:"granularity"`
+		Date        string `query:"date"`
+
+		// Start of the month and start of the next month as sent to the Wikipedia API, set by validate
+		rangeStart string
+		rangeEnd   string
+	}
+
+	EditsHandler struct {
+		client *wikimedia.Client
+	}
+)
+
+// List returns the number of edits made to a project in one month
+func (eh *EditsHandler) List(c echo.Context) (err error) {
+	var params Params
+	if err = c.Bind(&params); err != nil {
+		return
+	}
+
+	if err = params.validate(); err != nil {
+		return apierror.Validation(err)
+	}
+
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+
+	results, err := eh.client.Edits(c.Request().Context(), wikimedia.EditsRequest{
+		Project:     params.Project,
+		EditorType:  params.EditorType,
+		PageType:    params.PageType,
+		Granularity: params.Granularity,
+		Start:       params.rangeStart,
+		End:         params.rangeEnd,
+	})
+	if err != nil {
+		return apierror.From(err)
+	}
+
+	items := []Item{}
+	for _, result := range results {
+		items = append(items, Item{
+			Project:     strings.TrimSuffix(params.Project, ".org"),
+			Granularity: params.Granularity,
+			Timestamp:   timestamp(result.Timestamp),
+			EditorType:  params.EditorType,
+			PageType:    params.PageType,
+			Edits:       result.Edits,
+		})
+	}
+
+	return render.Items(c, format, items)
+}
+
+// Validate params, filling in defaults and the range boundaries the Wikipedia API needs
+func (p *Params) validate() (err error) {
+	if err = paramvalidator.Project(&p.Project); err != nil {
+		return
+	}
+	// Edits are not counted per hour
+	if err = paramvalidator.Granularity(&p.Granularity, paramvalidator.NewDailyGranularityValidator()); err != nil {
+		return
+	}
+
+	// Count all edits unless filtered by editor or page type
+	if err = paramvalidator.OrDefault(&p.EditorType, "all-editor-types", paramvalidator.NewEditorTypeValidator()); err != nil {
+		return
+	}
+	if err = paramvalidator.OrDefault(&p.PageType, "all-page-types", paramvalidator.NewPageTypeValidator()); err != nil {
+		return
+	}
+
+	// Validate date input
+	dv := paramvalidator.NewDateValidator()
+	if _, err = dv.Run(p.Date); err != nil {
+		return
+	}
+
+	// The Wikipedia API excludes the end of an edits range, so end it on the first day of the next month
+	df := paramformatter.NewDateFormatter()
+	start, end, err := df.Run(p.Date)
+	if err != nil {
+		return
+	}
+	lastDay, _ := time.Parse("20060102", end)
+	p.rangeStart, p.rangeEnd = start, lastDay.AddDate(0, 0, 1).Format("20060102")
+	return
+}
+
+// Return an ISO 8601 timestamp from the Wikipedia API in form YYYYMMDDHH, as for pageviews
+func timestamp(iso string) string {
+	t, err := time.Parse(time.RFC3339, iso)
+	if err != nil {
+		return iso
+	}
+
+	return t.Format("2006010215")
+}
+
+func NewEditsHandler(client *wikimedia.Client) *EditsHandler {
+	return &EditsHandler{client: client}
+}
@@ -0,0 +1,42 @@
+package edits
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestEditsHandler_List(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"items":[{"results":[{"timestamp":"2024-02-01T00:00:00.000Z","edits":3100000}]}]}`))
+	}))
+	defer server.Close()
+
+	e := echo.New()
+	eh := NewEditsHandler(wikimedia.NewClient(server.URL, server.Client()))
+	e.GET("/edits", eh.List)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/edits?date=202402&editor_type=user", nil))
+
+	// The range ends on the first day of the next month, which the Wikipedia API excludes
+	if expectedPath := "/edits/aggregate/en.wikipedia.org/user/all-page-types/monthly/20240201/20240301"; path != expectedPath {
+		t.Errorf("EditsHandler.List requests path %q; Expected %q", path, expectedPath)
+	}
+
+	var items []Item
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+		t.Fatalf("EditsHandler.List returns undecodable body: %v", err)
+	}
+
+	expected := Item{Project: "en.wikipedia", Granularity: "monthly", Timestamp: "2024020100", EditorType: "user", PageType: "all-page-types", Edits: 3100000}
+	if len(items) != 1 || items[0] != expected {
+		t.Errorf("EditsHandler.List returns %+v; Expected [%+v]", items, expected)
+	}
+}
@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Health reports whether the server should receive traffic. It is safe for concurrent use once every
+	// check is registered
+	Health struct {
+		draining atomic.Bool
+		checks   []namedCheck
+	}
+
+	// Check returns an error if a dependency the server needs to serve requests is unavailable
+	Check func(ctx context.Context) error
+
+	// Report is the body of a readiness probe
+	Report struct {
+		// StatusOK, StatusUnavailable, or StatusDraining once the server is shutting down
+		Status string        `json:"status"`
+		Checks []CheckResult `json:"checks"`
+	}
+
+	// CheckResult is the outcome of one dependency check
+	CheckResult struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	namedCheck struct {
+		name  string
+		check Check
+	}
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+
+	// How long a single check may take. Kubernetes probes should allow longer than this
+	checkTimeout = 2 * time.Second
+)
+
+// Register adds a dependency check run by every readiness probe. Register every check before serving
+func (h *Health) Register(name string, check Check) {
+	h.checks = append(h.checks, namedCheck{name, check})
+}
+
+// Drain marks the server as shutting down, so load balancers stop sending it new requests
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called
+func (h *Health) Draining() bool {
+	return h.draining.Load()
+}
+
+// Healthcheck probe
+// Kept for existing probes. Fails with a 503 once the server is draining, but runs no dependency checks
+func (h *Health) Healthcheck(c echo.Context) error {
+	if h.Draining() {
+		return c.String(http.StatusServiceUnavailable, StatusDraining)
+	}
+
+	return c.String(http.StatusOK, StatusOK)
+}
+
+// Livez probe
+// For Kubernetes liveness probes. Succeeds for as long as the server can answer, since restarting it would
+// not fix a broken dependency
+func (h *Health) Livez(c echo.Context) error {
+	return c.String(http.StatusOK, StatusOK)
+}
+
+// Readyz probe
+// For Kubernetes readiness probes. Runs every registered check and responds with a Report, failing with a 503
+// if any check fails or the server is draining
+func (h *Health) Readyz(c echo.Context) error {
+	report := h.Ready(c.Request().Context())
+	if report.Status != StatusOK {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// Ready runs every registered check at once, reporting results in the order the checks were registered
+func (h *Health) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: make([]CheckResult, len(h.checks))}
+	var wg sync.WaitGroup
+	for i, nc := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = CheckResult{Name: nc.name, Status: StatusOK}
+			if err := nc.check(ctx); err != nil {
+				report.Checks[i].Status, report.Checks[i].Error = StatusUnavailable, err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	// Still report checks while draining, so they can be told apart from the shutdown
+	if h.Draining() {
+		report.Status = StatusDraining
+	}
+
+	return report
+}
+
+// Cached returns a check that reuses the result of check for ttl, e.g. so frequent probes do not add load
+// to an upstream API. Concurrent probes wait on a single call rather than each calling check
+func Cached(check Check, ttl time.Duration) Check {
+	var mu sync.Mutex
+	var err error
+	var expiresAt time.Time
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if time.Now().Before(expiresAt) {
+			return err
+		}
+
+		err = check(ctx)
+		expiresAt = time.Now().Add(ttl)
+		return err
+	}
+}
+
+func NewHealth() *Health {
+	return &Health{}
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestHealth_Healthcheck(t *testing.T) {
+	h := NewHealth()
+	e := echo.New()
+	e.GET("/healthcheck", h.Healthcheck)
+
+	testCases := []struct {
+		drain          bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{false, http.StatusOK, "ok"},
+		{true, http.StatusServiceUnavailable, "draining"},
+	}
+
+	for _, tc := range testCases {
+		if tc.drain {
+			h.Drain()
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthcheck", nil))
+
+		if rec.Code != tc.expectedStatus || rec.Body.String() != tc.expectedBody {
+			t.Errorf("Healthcheck with draining %t responds %d %q; Expected %d %q", tc.drain, rec.Code, rec.Body, tc.expectedStatus, tc.expectedBody)
+		}
+	}
+}
+
+func TestHealth_Livez(t *testing.T) {
+	h := NewHealth()
+	h.Register("upstream", func(ctx context.Context) error { return errors.New("unreachable") })
+	h.Drain()
+	e := echo.New()
+	e.GET("/livez", h.Livez)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != StatusOK {
+		t.Errorf("Livez with a failing check while draining responds %d %q; Expected %d %q", rec.Code, rec.Body, http.StatusOK, StatusOK)
+	}
+}
+
+func TestHealth_Readyz(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	testCases := []struct {
+		checks         []Check
+		drain          bool
+		expectedStatus int
+		expectedReport Report
+	}{
+		{
+			[]Check{ok, ok}, false, http.StatusOK,
+			Report{StatusOK, []CheckResult{{"upstream", StatusOK, ""}, {"cache", StatusOK, ""}}},
+		},
+		{
+			[]Check{ok, failing}, false, http.StatusServiceUnavailable,
+			Report{StatusUnavailable, []CheckResult{{"upstream", StatusOK, ""}, {"cache", StatusUnavailable, "connection refused"}}},
+		},
+		{
+			[]Check{ok, ok}, true, http.StatusServiceUnavailable,
+			Report{StatusDraining, []CheckResult{{"upstream", StatusOK, ""}, {"cache", StatusOK, ""}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		h := NewHealth()
+		h.Register("upstream", tc.checks[0])
+		h.Register("cache", tc.checks[1])
+		if tc.drain {
+			h.Drain()
+		}
+		e := echo.New()
+		e.GET("/readyz", h.Readyz)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var report Report
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		if rec.Code != tc.expectedStatus || !reflect.DeepEqual(report, tc.expectedReport) {
+			t.Errorf("Readyz responds %d %+v; Expected %d %+v", rec.Code, report, tc.expectedStatus, tc.expectedReport)
+		}
+	}
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	check := Cached(func(ctx context.Context) error {
+		calls++
+		return errors.New("unreachable")
+	}, time.Hour)
+
+	for range 3 {
+		if err := check(context.Background()); err == nil {
+			t.Errorf("Cached check returns err = nil; Expected the cached error")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Cached check calls the underlying check %d times within its ttl; Expected 1", calls)
+	}
+}
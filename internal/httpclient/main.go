@@ -1,15 +1,26 @@
 package httpclient
 
 import (
+	"net"
 	"net/http"
 	"time"
+	"wikiviews/internal/config"
 )
 
-func NewHttpClient() *http.Client {
+func NewHttpClient(settings config.Upstream) *http.Client {
 	tr := &http.Transport{
-		MaxIdleConns:       10,
-		IdleConnTimeout:    30 * time.Second,
-		DisableCompression: true,
+		DialContext: (&net.Dialer{
+			Timeout:   time.Duration(settings.DialTimeout),
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		// Requests go to the metrics API and to each project's wiki, so the idle pool is shared across hosts
+		// with each held to its own share
+		MaxIdleConns:          settings.MaxIdleConns,
+		MaxIdleConnsPerHost:   settings.MaxIdleConnsPerHost,
+		IdleConnTimeout:       time.Duration(settings.IdleConnTimeout),
+		TLSHandshakeTimeout:   time.Duration(settings.TLSHandshakeTimeout),
+		ResponseHeaderTimeout: time.Duration(settings.ResponseHeaderTimeout),
+		DisableCompression:    true,
 	}
 	return &http.Client{Transport: tr}
 }
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type loggerKey struct{}
+
+// Query params logged with every request, when set, so requests for an article can be found across logs
+var loggedParams = []string{"article", "date", "start", "end"}
+
+// New returns a logger writing one JSON object per line to w, dropping records below level
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// WithLogger returns a copy of ctx carrying logger, picked up by FromContext in everything called with it
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger if it has none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// Middleware gives every request a logger tagged with its request id, which must already be set in the
+// X-Request-ID response header by middleware.RequestID, and logs the request once it has been served, with the
+// status any error was rendered with by apierror.Middleware.
+// Handlers and the Wikimedia client log through it with FromContext, so every line of a request can be correlated
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			start := time.Now()
+			req := c.Request()
+			logger := slog.Default().With("request_id", c.Response().Header().Get(echo.HeaderXRequestID))
+			// Link logs to the request's trace, when it is traced
+			if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+				logger = logger.With("trace_id", sc.TraceID().String())
+			}
+			c.SetRequest(req.WithContext(WithLogger(req.Context(), logger)))
+			err = next(c)
+
+			attrs := []any{
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("route", c.Path()),
+				slog.Int("status", c.Response().Status),
+				slog.Int64("bytes_out", c.Response().Size),
+				slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+				slog.String("remote_ip", c.RealIP()),
+			}
+			for _, param := range loggedParams {
+				if value := c.QueryParam(param); len(value) > 0 {
+					attrs = append(attrs, slog.String(param, value))
+				}
+			}
+			logger.Info("request", attrs...)
+
+			return
+		}
+	}
+}
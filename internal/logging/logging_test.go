@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(New(&buf, slog.LevelInfo))
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(Middleware())
+	e.GET("/pageviews", func(c echo.Context) error {
+		FromContext(c.Request().Context()).Info("upstream request")
+		return c.NoContent(http.StatusBadRequest)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/pageviews?article=Orca&date=202402", nil)
+	req.Header.Set(echo.HeaderXRequestID, "7b3c")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	var lines []map[string]any
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var line map[string]any
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatalf("Middleware writes a log line that is not JSON: %v", err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("Middleware writes %d log lines; Expected 2", len(lines))
+	}
+
+	for _, line := range lines {
+		if line["request_id"] != "7b3c" {
+			t.Errorf("Middleware logs %v with request_id %v; Expected the propagated id %q", line["msg"], line["request_id"], "7b3c")
+		}
+	}
+
+	request := lines[1]
+	expected := map[string]any{"msg": "request", "route": "/pageviews", "status": float64(http.StatusBadRequest), "article": "Orca", "date": "202402"}
+	for k, v := range expected {
+		if request[k] != v {
+			t.Errorf("Middleware logs the request with %s = %v; Expected %v", k, request[k], v)
+		}
+	}
+}
+
+func TestFromContext_Default(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Errorf("FromContext without a logger does not return the default logger")
+	}
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric the server exports, along with Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wikiviews_http_requests_total",
+		Help: "Requests served, by route and status.",
+	}, []string{"method", "route", "status"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wikiviews_http_request_duration_seconds",
+		Help:    "Time taken to serve requests, by route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wikiviews_upstream_requests_total",
+		Help: "Attempted calls to the Wikipedia API, by endpoint and status. Status is error for calls that got no response and circuit_open for calls failed fast by the circuit breaker.",
+	}, []string{"endpoint", "status"})
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "wikiviews_upstream_request_duration_seconds",
+		Help:    "Time taken for the Wikipedia API to respond with headers or fail, by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	validationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wikiviews_validation_failures_total",
+		Help: "Requests rejected for an invalid param, by param and reason.",
+	}, []string{"param", "reason"})
+
+	rateLimited = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "wikiviews_rate_limited_total",
+		Help: "Requests rejected by the rate limiter, whether per IP address or per API key.",
+	})
+
+	quotaExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "wikiviews_quota_exceeded_total",
+		Help: "Requests rejected for exceeding the daily quota of their API key, by key name.",
+	}, []string{"key"})
+)
+
+// Status label for upstream calls that did not get a response
+const (
+	StatusError       = "error"
+	StatusCircuitOpen = "circuit_open"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		upstreamRequests, upstreamDuration,
+		validationFailures,
+		rateLimited,
+		quotaExceeded,
+	)
+}
+
+// Middleware records the count and latency of every request by route and the status it was served with,
+// which errors must already have been rendered with by apierror.Middleware. The route is the registered path,
+// e.g. /pageviews, so the labels stay bounded whatever clients request
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			start := time.Now()
+			err = next(c)
+
+			route := c.Path()
+			if len(route) == 0 {
+				route = "unmatched"
+			}
+			labels := prometheus.Labels{
+				"method": c.Request().Method,
+				"route":  route,
+				"status": strconv.Itoa(c.Response().Status),
+			}
+			httpRequests.With(labels).Inc()
+			httpDuration.With(labels).Observe(time.Since(start).Seconds())
+
+			return
+		}
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() echo.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
+
+// ObserveUpstream records a call to endpoint of the Wikipedia API that ended after d with status, either the
+// HTTP status it responded with or StatusError if it got no response
+func ObserveUpstream(endpoint, status string, d time.Duration) {
+	upstreamRequests.WithLabelValues(endpoint, status).Inc()
+	upstreamDuration.WithLabelValues(endpoint).Observe(d.Seconds())
+}
+
+// UpstreamRejected records a call to endpoint of the Wikipedia API failed fast by the circuit breaker
+func UpstreamRejected(endpoint string) {
+	upstreamRequests.WithLabelValues(endpoint, StatusCircuitOpen).Inc()
+}
+
+// ValidationFailed records a request rejected for param, e.g. article, for reason, e.g. empty
+func ValidationFailed(param, reason string) {
+	validationFailures.WithLabelValues(param, reason).Inc()
+}
+
+// RateLimited records a request rejected by the rate limiter
+func RateLimited() {
+	rateLimited.Inc()
+}
+
+// QuotaExceeded records a request rejected for exceeding the daily quota of the API key named key
+func QuotaExceeded(key string) {
+	quotaExceeded.WithLabelValues(key).Inc()
+}
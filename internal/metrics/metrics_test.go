@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	// Render errors inside the middleware, as apierror.Middleware does in the server
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			return nil
+		}
+	})
+	e.GET("/pageviews", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+	e.GET("/top", func(c echo.Context) error { return echo.NewHTTPError(http.StatusBadRequest, "bad") })
+	e.GET("/edits", func(c echo.Context) error { return errors.New("boom") })
+	e.GET("/metrics", Handler())
+
+	testCases := []struct {
+		path   string
+		route  string
+		status string
+	}{
+		{"/pageviews?article=Orca", "/pageviews", "200"},
+		{"/top", "/top", "400"},
+		{"/edits", "/edits", "500"},
+		{"/Orca", "unmatched", "404"},
+	}
+
+	for _, tc := range testCases {
+		before := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, tc.route, tc.status))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+		after := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, tc.route, tc.status))
+		if after-before != 1 {
+			t.Errorf("Middleware for GET %s counts %v requests with route %q and status %s; Expected 1", tc.path, after-before, tc.route, tc.status)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `wikiviews_http_requests_total{method="GET",route="/pageviews",status="200"}`) {
+		t.Errorf("Handler responds without the request count for /pageviews:\n%s", rec.Body)
+	}
+}
@@ -0,0 +1,34 @@
+// Package metricstest reads metrics recorded by the metrics package, for tests of the packages recording them
+package metricstest
+
+import (
+	"testing"
+	"wikiviews/internal/metrics"
+)
+
+// ValidationFailures returns how many validation failures have been counted for param and reason
+func ValidationFailures(t testing.TB, param, reason string) (count float64) {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, family := range families {
+		if family.GetName() != "wikiviews_validation_failures_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range m.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["param"] == param && labels["reason"] == reason {
+				count = m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return
+}
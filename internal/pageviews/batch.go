@@ -0,0 +1,172 @@
+package pageviews
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/logging"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	BatchRequest struct {
+		Params
+		Articles []string `json:"articles"`
+	}
+	// BatchResult is the outcome for one article, with the fields of an error body when it failed
+	BatchResult struct {
+		Article        string   `json:"article"`
+		Status         int      `json:"status"`
+		Items          []Item   `json:"items,omitempty"`
+		Code           string   `json:"code,omitempty"`
+		Message        string   `json:"error,omitempty"`
+		UpstreamStatus int      `json:"upstream_status,omitempty"`
+		RequestID      string   `json:"request_id,omitempty"`
+		Suggestions    []string `json:"suggestions,omitempty"`
+	}
+)
+
+const (
+	// Most articles a single batch may ask for
+	maxBatchArticles = 500
+	// Most upstream requests a single batch may have in flight at once
+	batchWorkers = 8
+)
+
+// Batch queries many articles for the same params in one call.
+// An article that fails validation or is not found upstream gets an error in its own result without failing the whole batch
+func (ph *PageviewsHandler) Batch(c echo.Context) (err error) {
+	var batch BatchRequest
+	if err = c.Bind(&batch); err != nil {
+		return
+	}
+
+	if len(batch.Articles) == 0 {
+		return apierror.Validation(paramvalidator.NewError("articles", paramvalidator.ReasonEmpty, "error: articles param is invalid: param cannot be empty"))
+	}
+
+	if len(batch.Articles) > maxBatchArticles {
+		return apierror.Validation(paramvalidator.NewError("articles", paramvalidator.ReasonInvalid, "error: articles param is invalid: param must not contain more than %d articles", maxBatchArticles))
+	}
+
+	if err = batch.Validate(c.Request().Context()); err != nil {
+		return apierror.Validation(err)
+	}
+
+	// Results hold a list of items each, so have no delimited form
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+	if format != render.JSON && format != render.NDJSON {
+		return apierror.Validation(paramvalidator.NewError("format", paramvalidator.ReasonInvalid, "error: format param %s is invalid: batch results can only be json or ndjson", format))
+	}
+
+	// Stop querying outstanding articles once the client has gone, which a failed write also reveals
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	// Fan out to the Wikipedia API with a bounded pool of workers, closing done[i] once article i has its result
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	results := make([]BatchResult, len(batch.Articles))
+	done := make([]chan struct{}, len(batch.Articles))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	workersDone := make(chan struct{})
+	go func() {
+		defer close(workersDone)
+		parallel(ctx, len(batch.Articles), func(ctx context.Context, i int) {
+			results[i] = ph.fetchResult(ctx, batch.Params, batch.Articles[i], requestID)
+			close(done[i])
+		})
+	}()
+
+	// However the batch ends, stop outstanding articles and wait for every worker, so none outlives the request
+	defer func() {
+		cancel()
+		<-workersDone
+	}()
+
+	// Stream results in request order, each as soon as it and every result before it are ready
+	stream := render.NewStream[BatchResult](c, format)
+	for i := range results {
+		<-done[i]
+		if err = stream.Write(results[i]); err != nil {
+			logging.FromContext(ctx).Error("error streaming batch results", "error", err)
+			return nil
+		}
+	}
+
+	return stream.Close()
+}
+
+// Query one article of a batch, reporting any failure in the result rather than as an error
+func (ph *PageviewsHandler) fetchResult(ctx context.Context, params Params, article, requestID string) BatchResult {
+	// Skip articles still queued once the batch is cancelled
+	if err := ctx.Err(); err != nil {
+		return failedResult(article, apierror.From(err), requestID)
+	}
+
+	items, _, err := ph.query(ctx, params, article, nil)
+	if err != nil {
+		e := apierror.From(err)
+		logging.FromContext(ctx).Info("batch article failed", "article", article, "error", err, "status", e.Status, "code", e.Code)
+		return failedResult(article, e, requestID)
+	}
+
+	return BatchResult{Article: article, Status: http.StatusOK, Items: items}
+}
+
+// Build the result of a failed article with the fields of its error body, including the batch's request id
+// as HTTPErrorHandler would set it
+func failedResult(article string, e *apierror.Error, requestID string) BatchResult {
+	return BatchResult{
+		Article:        article,
+		Status:         e.Status,
+		Code:           e.Code,
+		Message:        e.Message,
+		UpstreamStatus: e.UpstreamStatus,
+		RequestID:      requestID,
+		Suggestions:    e.Suggestions,
+	}
+}
+
+// Context key marking work already running in a pool of workers
+type workerKey struct{}
+
+// Call fn for every index up to n with at most batchWorkers calls in flight at once, returning when all are done.
+// Calls made from within fn, e.g. fetching redirects of a batch article, run one after another in its worker,
+// so however the work nests a request never has more than batchWorkers upstream calls in flight
+func parallel(ctx context.Context, n int, fn func(ctx context.Context, i int)) {
+	if ctx.Value(workerKey{}) != nil {
+		for i := range n {
+			fn(ctx, i)
+		}
+		return
+	}
+	ctx = context.WithValue(ctx, workerKey{}, struct{}{})
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(batchWorkers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(ctx, i)
+			}
+		}()
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
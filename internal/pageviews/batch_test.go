@@ -0,0 +1,194 @@
+package pageviews
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Serve the Batch handler against upstream, returning a server for it
+func newBatchServer(t *testing.T, upstream *httptest.Server) *httptest.Server {
+	client := wikimedia.NewClient(upstream.URL, upstream.Client(), wikimedia.WithProjectUrl(upstream.URL+"/{project}/w"))
+	ph := NewPageviewsHandler(client, cache.NewMemoryCache(10))
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	e.Use(middleware.RequestID())
+	e.POST("/pageviews/batch", ph.Batch)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestPageviewsHandler_Batch_NDJSON(t *testing.T) {
+	upstream := newStubWikimedia(t, stubWikimedia{views: map[string]int32{"Orca": 10, "Michael_Phelps": 20}})
+	server := newBatchServer(t, upstream)
+
+	body := `{"articles":["Orca","Unknown_Article","Michael_Phelps"],"date":"202402"}`
+	response, err := http.Post(server.URL+"/pageviews/batch?format=ndjson", echo.MIMEApplicationJSON, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	// One result per line, in request order
+	decoder := json.NewDecoder(response.Body)
+	expected := []struct {
+		article string
+		status  int
+		code    string
+	}{{"Orca", http.StatusOK, ""}, {"Unknown_Article", http.StatusNotFound, apierror.CodeArticleNotFound}, {"Michael_Phelps", http.StatusOK, ""}}
+	for _, e := range expected {
+		var result BatchResult
+		if err = decoder.Decode(&result); err != nil {
+			t.Fatalf("Batch with format=ndjson returns undecodable line: %v", err)
+		}
+		if result.Article != e.article || result.Status != e.status || result.Code != e.code {
+			t.Errorf("Batch with format=ndjson returns %s %d %q; Expected %s %d %q", result.Article, result.Status, result.Code, e.article, e.status, e.code)
+		}
+
+		// Failed articles carry the request id, as error bodies do
+		requestID := ""
+		if e.status != http.StatusOK {
+			requestID = response.Header.Get(echo.HeaderXRequestID)
+		}
+		if result.RequestID != requestID {
+			t.Errorf("Batch with format=ndjson returns %s with request_id %q; Expected %q", result.Article, result.RequestID, requestID)
+		}
+	}
+}
+
+func TestPageviewsHandler_Batch_SumRedirects(t *testing.T) {
+	// Many articles with many redirects each, which a batch must still fetch through one bounded pool of workers
+	stub := stubWikimedia{views: map[string]int32{}, redirects: map[string][]string{}, inFlight: &inFlight{}}
+	var articles []string
+	for i := range 2 * batchWorkers {
+		article := fmt.Sprintf("Article_%d", i)
+		articles = append(articles, article)
+		stub.views[article] = 10
+		for j := range batchWorkers {
+			redirect := fmt.Sprintf("Redirect_%d_%d", i, j)
+			stub.redirects[article] = append(stub.redirects[article], redirect)
+			stub.views[redirect] = 1
+		}
+	}
+	server := newBatchServer(t, newStubWikimedia(t, stub))
+
+	body, _ := json.Marshal(map[string]any{"articles": articles, "date": "202402", "sum_redirects": true})
+	response, err := http.Post(server.URL+"/pageviews/batch", echo.MIMEApplicationJSON, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	var results []BatchResult
+	if err = json.NewDecoder(response.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != http.StatusOK || len(result.Items) != 1 || result.Items[0].Views != 10+batchWorkers {
+			t.Errorf("Batch with sum_redirects returns %+v; Expected 200 with %d views", result, 10+batchWorkers)
+		}
+	}
+
+	if stub.inFlight.peak > batchWorkers {
+		t.Errorf("Batch with sum_redirects has %d upstream requests in flight at once; Expected at most %d", stub.inFlight.peak, batchWorkers)
+	}
+}
+
+func TestPageviewsHandler_Batch_Cancelled(t *testing.T) {
+	// An upstream that hangs, reporting each request it receives and each that is cancelled
+	received := make(chan struct{}, 100)
+	cancelled := make(chan struct{}, 100)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-r.Context().Done()
+		cancelled <- struct{}{}
+	}))
+	t.Cleanup(upstream.Close)
+	server := newBatchServer(t, upstream)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/pageviews/batch",
+		strings.NewReader(`{"articles":["Orca","Michael_Phelps"],"date":"202402"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	go http.DefaultClient.Do(request)
+
+	// Disconnect once upstream is being queried, which must cancel the outstanding upstream request
+	<-received
+	cancel()
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Batch does not cancel upstream requests when the client disconnects")
+	}
+}
+
+// Counts upstream calls in flight, holding every call but those for Article_0 open for a moment
+// whether or not its request is cancelled
+type slowTransport struct {
+	inFlight atomic.Int32
+}
+
+func (st *slowTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	st.inFlight.Add(1)
+	defer st.inFlight.Add(-1)
+
+	if !strings.Contains(r.URL.Path, "/Article_0/") {
+		time.Sleep(20 * time.Millisecond)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// Fails every write, as to a client that has gone
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (fw failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("client gone")
+}
+
+func TestPageviewsHandler_Batch_WriteFailure(t *testing.T) {
+	stub := stubWikimedia{views: map[string]int32{}}
+	var articles []string
+	for i := range 2 * batchWorkers {
+		article := fmt.Sprintf("Article_%d", i)
+		articles = append(articles, article)
+		stub.views[article] = 10
+	}
+	upstream := newStubWikimedia(t, stub)
+
+	transport := &slowTransport{}
+	client := wikimedia.NewClient(upstream.URL, &http.Client{Transport: transport})
+	ph := NewPageviewsHandler(client, cache.NewMemoryCache(10))
+
+	e := echo.New()
+	e.POST("/pageviews/batch", ph.Batch)
+
+	body, _ := json.Marshal(map[string]any{"articles": articles, "date": "202402"})
+	request := httptest.NewRequest(http.MethodPost, "/pageviews/batch", bytes.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(failingWriter{httptest.NewRecorder()}, request)
+
+	if n := transport.inFlight.Load(); n != 0 {
+		t.Errorf("Batch returns after failing to write with %d upstream calls in flight; Expected 0", n)
+	}
+}
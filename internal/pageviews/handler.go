@@ -1,135 +1,315 @@
 package pageviews
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
 	"net/url"
-	"wikiviews/internal/httpclient"
+	"sort"
+	"strings"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/logging"
 	"wikiviews/internal/paramformatter"
 	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+	"wikiviews/internal/tracing"
+	"wikiviews/internal/wikimedia"
 
 	"github.com/labstack/echo/v4"
 )
 
 type (
+	// Item is one data point of a pageviews series
 	Item struct {
-		Article   string `json:"article"`
-		Timestamp string `json:"timestamp"`
-		Views     int32  `json:"views"`
-	}
-	ResponseData struct {
-		Items []Item `json:"items"`
+		wikimedia.ArticleItem
+		// Canonical is the title that redirects were resolved to, when resolution was asked for
+		Canonical string `json:"canonical,omitempty"`
 	}
 
-	PageviewsHandler struct{}
+	PageviewsHandler struct {
+		client *wikimedia.Client
+		cache  cache.Cache
+	}
 )
 
 const (
-	baseUrl   = "https://wikimedia.org/api/rest_v1/metrics/pageviews/per-article/en.wikipedia.org/all-access/all-agents"
-	userAgent = "WikiViews/1.0"
+	cacheHeader = "X-Cache"
+	// Most title search candidates to suggest for an article with no results
+	searchLimit = 5
+	// Most redirects whose views are summed into an article
+	maxRedirects = 50
 )
 
 func (ph *PageviewsHandler) List(c echo.Context) (err error) {
-	// Query escape all incoming article params
-	article := url.QueryEscape(c.QueryParam("article"))
+	var params Params
+	if err = c.Bind(&params); err != nil {
+		return
+	}
 
-	// Validate title input
-	tv := paramvalidator.NewTitleValidator()
-	tvok, err := tv.Run(article)
-	if !tvok {
-		log.Println("error:", err)
-		return c.JSON(http.StatusBadRequest, errorMessage((err)))
+	if err = params.Validate(c.Request().Context()); err != nil {
+		return apierror.Validation(err)
 	}
 
-	// Validate date input
-	date := c.QueryParam("date")
-	dv := paramvalidator.NewDateValidator()
-	dvok, err := dv.Run(date)
-	if !dvok {
-		log.Println("error:", err)
-		return c.JSON(http.StatusBadRequest, errorMessage((err)))
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
 	}
 
-	// Return start and end date params that Wikipedia API needs from single data input
-	df := paramformatter.NewDateFormatter()
-	monthStart, monthEnd, err := df.Run(date)
+	// Stream items fetched from the Wikipedia API to the client as they are decoded. Items are only ever
+	// streamed on a cache miss, so report one before the first is sent
+	stream := render.NewStream[Item](c, format)
+	emit := func(item Item) error {
+		if !stream.Started() {
+			c.Response().Header().Set(cacheHeader, "MISS")
+		}
+		return stream.Write(item)
+	}
+
+	items, hit, err := ph.query(c.Request().Context(), params, c.QueryParam("article"), emit)
 	if err != nil {
-		log.Println("error:", err)
-		return c.JSON(http.StatusBadRequest, errorMessage((err)))
+		// Once streaming has begun the status is sent, so all that is left is to cut the response short
+		if stream.Started() {
+			logging.FromContext(c.Request().Context()).Error("error streaming response", "error", err)
+			return nil
+		}
+		return
+	}
+
+	// Write any items that were not streamed, i.e. served from the cache or summed across redirects,
+	// reporting whether the response was served from the cache
+	if !stream.Started() {
+		if hit {
+			c.Response().Header().Set(cacheHeader, "HIT")
+		} else {
+			c.Response().Header().Set(cacheHeader, "MISS")
+		}
+
+		for _, item := range items {
+			if err = stream.Write(item); err != nil {
+				return
+			}
+		}
 	}
 
-	url := fmt.Sprintf("%s/%s/monthly/%s/%s", baseUrl, article, monthStart, monthEnd)
-	client := httpclient.NewHttpClient()
+	return stream.Close()
+}
+
+// Query returns the pageviews of one article for params, which must have passed Validate, exactly as /pageviews
+// would. It serves callers outside HTTP, e.g. the wikiviews CLI. Any error is an *apierror.Error
+func (ph *PageviewsHandler) Query(ctx context.Context, params Params, article string) ([]Item, error) {
+	items, _, err := ph.query(ctx, params, article, nil)
+	return items, err
+}
+
+// Query one article, falling back to a title search when the Wikipedia API has no results for it.
+// When emit is set, items fetched from the Wikipedia API are passed to it as they are decoded, unless they are
+// to be summed across redirects. Any error is an *apierror.Error
+func (ph *PageviewsHandler) query(ctx context.Context, params Params, article string, emit func(Item) error) (items []Item, hit bool, err error) {
+	// Tag everything logged for this article, e.g. its upstream calls, which a batch interleaves with others
+	ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("article", article))
 
-	// Create a new HTTP GET request with our User-Agent header
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	// Validate title input against the naming rules of the project, before it is escaped for the Wikipedia API
+	tv := paramvalidator.NewTitleValidator(params.Project)
+	err = tracing.Run(ctx, "TitleValidator.Run", func(context.Context) (err error) {
+		_, err = tv.Run(article)
+		return
+	})
 	if err != nil {
-		log.Println("error:", err)
-		return err
+		return nil, false, apierror.Validation(err)
 	}
-	req.Header.Set("User-Agent", userAgent)
-	log.Printf("sending GET request to Wikipedia endpoint: %s\n", req.URL)
 
-	// Send the request to the Wikipedia API
-	response, err := client.Do(req)
+	// When asked to, follow redirects first so views are counted against the canonical article
+	title := article
+	if params.resolvesRedirects() {
+		if title, err = ph.client.ResolveRedirect(ctx, params.Project, article); err != nil {
+			return nil, false, apierror.From(err)
+		}
+	}
+
+	// Summing needs every item before any can be sent. Otherwise label streamed items with the title
+	// finally queried, which autocorrect may yet change
+	if params.SumRedirects {
+		emit = nil
+	} else if emit != nil && params.resolvesRedirects() {
+		send := emit
+		emit = func(item Item) error {
+			item.Canonical = title
+			return send(item)
+		}
+	}
+
+	items, hit, err = ph.cachedFetch(ctx, params, title, emit)
+	if errors.Is(err, wikimedia.ErrNotFound) {
+		// Look up real articles with similar titles, most relevant first
+		candidates := ph.search(ctx, params.Project, article)
+
+		// When asked to, retry with the top candidate in place of the article param
+		if params.Autocorrect && len(candidates) > 0 && candidates[0] != title {
+			logging.FromContext(ctx).Info("autocorrecting article param", "autocorrected", candidates[0])
+			title = candidates[0]
+			items, hit, err = ph.cachedFetch(ctx, params, title, emit)
+		}
+
+		if errors.Is(err, wikimedia.ErrNotFound) {
+			return nil, false, notFound(params.Project, article, candidates)
+		}
+	}
 	if err != nil {
-		log.Println("response error:", err)
-		return
+		return nil, false, apierror.From(err)
+	}
+
+	if params.SumRedirects {
+		var redirectsHit bool
+		if items, redirectsHit, err = ph.sumRedirects(ctx, params, title, items); err != nil {
+			return nil, false, apierror.From(err)
+		}
+		hit = hit && redirectsHit
+	}
+
+	if params.resolvesRedirects() {
+		for i := range items {
+			items[i].Canonical = title
+		}
 	}
-	defer response.Body.Close()
 
-	// Read the response body
-	body, err := io.ReadAll(response.Body)
+	return
+}
+
+// Add the views of every article redirecting to canonical onto its items, since the Wikipedia API counts
+// views of redirects separately. hit reports whether every redirect was served from the cache
+func (ph *PageviewsHandler) sumRedirects(ctx context.Context, params Params, canonical string, items []Item) (summed []Item, hit bool, err error) {
+	redirects, err := ph.client.Redirects(ctx, params.Project, canonical, maxRedirects)
 	if err != nil {
-		log.Println("error reading response:", err)
 		return
 	}
 
-	// Handle 404 error response code
-	if response.StatusCode == http.StatusNotFound {
-		pf := paramformatter.NewTitleFormatter()
-		baseMessage := "error: query for article param: %s did not return any results. Consider titlizing article param as %s."
+	// Fetch the redirects with a bounded pool of workers, or one after another within a batch's worker
+	redirectItems := make([][]Item, len(redirects))
+	redirectHits := make([]bool, len(redirects))
+	errs := make([]error, len(redirects))
+	parallel(ctx, len(redirects), func(ctx context.Context, i int) {
+		redirectItems[i], redirectHits[i], errs[i] = ph.cachedFetch(ctx, params, redirects[i], nil)
+	})
 
-		if pf.IsSingleWord(article) {
-			fullMessage := fmt.Sprintf(baseMessage, article, pf.Run(article, true))
-			err = fmt.Errorf(fullMessage)
-		} else if pf.IsMultiWord(article) {
-			titleOptions := fmt.Sprintf("%s or %s", pf.Run(article, true), pf.Run(article, false))
-			fullMessage := fmt.Sprintf(baseMessage, article, titleOptions)
-			err = fmt.Errorf(fullMessage)
-		} else {
-			err = fmt.Errorf("no results found")
+	// Sum views per timestamp. A redirect with no views in the range is not found upstream, and adds nothing
+	views := make(map[string]int32)
+	for _, item := range items {
+		views[item.Timestamp] += item.Views
+	}
+
+	hit = true
+	for i := range redirects {
+		if errors.Is(errs[i], wikimedia.ErrNotFound) {
+			continue
+		}
+		if errs[i] != nil {
+			return nil, false, errs[i]
+		}
+
+		hit = hit && redirectHits[i]
+		for _, item := range redirectItems[i] {
+			if _, ok := views[item.Timestamp]; !ok {
+				items = append(items, item)
+			}
+			views[item.Timestamp] += item.Views
 		}
+	}
 
-		log.Println("error:", err)
-		return c.JSON(http.StatusNotFound, errorMessage((err)))
+	for i := range items {
+		items[i].Article = canonical
+		items[i].Views = views[items[i].Timestamp]
 	}
 
-	// Unmarshal the JSON response into a struct
-	var responseData ResponseData
-	err = json.Unmarshal(body, &responseData)
+	// Keep the summed range as one series ordered by timestamp
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp < items[j].Timestamp
+	})
+
+	return items, hit, nil
+}
+
+// Return the keys of articles on project whose titles best match article. Search is best effort,
+// so a failing search is logged and returns no candidates
+func (ph *PageviewsHandler) search(ctx context.Context, project, article string) (candidates []string) {
+	results, err := ph.client.SearchTitle(ctx, project, article, searchLimit)
 	if err != nil {
-		log.Println("error unmarshalling JSON:", err)
-		return
+		logging.FromContext(ctx).Warn("error searching titles", "error", err)
+		return nil
 	}
 
-	// Set response headers
-	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-	c.Response().WriteHeader(http.StatusOK)
+	for _, result := range results {
+		candidates = append(candidates, result.Key)
+	}
 
-	// Print the message from the JSON response
-	return json.NewEncoder(c.Response()).Encode(responseData.Items)
+	return
 }
 
-func errorMessage(err error) map[string]string {
-	return map[string]string{
-		"error": err.Error(),
+// Build the 404 for an article with no results, suggesting alternative casings of the title in the message
+// and any real articles found by title search
+func notFound(project, article string, candidates []string) *apierror.Error {
+	escaped := url.QueryEscape(article)
+	pf := paramformatter.NewTitleFormatter(project)
+	baseMessage := "error: query for article param: %s did not return any results. Consider titlizing article param as %s."
+
+	message := "no results found"
+	if suggestions := pf.Suggestions(escaped); len(suggestions) > 0 {
+		message = fmt.Sprintf(baseMessage, escaped, strings.Join(suggestions, " or "))
 	}
+
+	e := apierror.New(http.StatusNotFound, apierror.CodeArticleNotFound, message)
+	e.UpstreamStatus = http.StatusNotFound
+	e.Suggestions = candidates
+	return e
+}
+
+// Query one article, serving it from the cache when possible. Fresh results are passed to emit as they are
+// decoded when it is set
+func (ph *PageviewsHandler) cachedFetch(ctx context.Context, params Params, article string, emit func(Item) error) (items []Item, hit bool, err error) {
+	key := strings.Join([]string{params.Project, params.Access, params.Agent, url.QueryEscape(article), params.Granularity, params.rangeStart, params.rangeEnd}, "/")
+	ttl := cache.TTL(params.periodEnd(), time.Now())
+
+	return cache.Fetch(ctx, ph.cache, key, ttl, func() ([]Item, error) {
+		return ph.fetch(ctx, params, article, emit)
+	})
+}
+
+// Query the Wikipedia API for one article using validated params, passing each item to emit as it is decoded
+// when it is set. Emitted items keep the order the Wikipedia API returns them in, which is by timestamp, while
+// the items returned, and so cached, are sorted by timestamp whatever that order
+func (ph *PageviewsHandler) fetch(ctx context.Context, params Params, article string, emit func(Item) error) (items []Item, err error) {
+	request := wikimedia.PerArticleRequest{
+		Project:     params.Project,
+		Access:      params.Access,
+		Agent:       params.Agent,
+		Article:     article,
+		Granularity: params.Granularity,
+		Start:       params.rangeStart,
+		End:         params.rangeEnd,
+	}
+
+	err = ph.client.PerArticleEach(ctx, request, func(articleItem wikimedia.ArticleItem) error {
+		item := Item{ArticleItem: articleItem}
+		items = append(items, item)
+		if emit != nil {
+			return emit(item)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// Return the range as one series ordered by timestamp, as PerArticle does
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp < items[j].Timestamp
+	})
+
+	return
 }
 
-func NewPageviewsHandler() *PageviewsHandler {
-	return &PageviewsHandler{}
+func NewPageviewsHandler(client *wikimedia.Client, responseCache cache.Cache) *PageviewsHandler {
+	return &PageviewsHandler{client: client, cache: responseCache}
 }
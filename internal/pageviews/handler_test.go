@@ -0,0 +1,270 @@
+package pageviews
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/cache"
+	"wikiviews/internal/metrics/metricstest"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Data served by a stub Wikimedia API
+type stubWikimedia struct {
+	// Monthly views per article. Any other article is not found
+	views map[string]int32
+	// Results of any title search
+	search []wikimedia.SearchResult
+	// Titles redirecting to each canonical article
+	redirects map[string][]string
+	// Timestamps of every article's data points, in the order they are returned. Defaults to 2024020100
+	timestamps []string
+	// Records the most requests in flight at once when set, holding each open for a moment so they overlap
+	inFlight *inFlight
+}
+
+// Counts requests in flight, and the most there have been at once
+type inFlight struct {
+	mu      sync.Mutex
+	current int
+	peak    int
+}
+
+func (f *inFlight) start() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current++
+	f.peak = max(f.peak, f.current)
+}
+
+func (f *inFlight) end() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.current--
+}
+
+func newStubWikimedia(t *testing.T, stub stubWikimedia) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if stub.inFlight != nil {
+			stub.inFlight.start()
+			defer stub.inFlight.end()
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/search/title"):
+			json.NewEncoder(w).Encode(map[string]any{"pages": stub.search})
+			return
+		case strings.HasSuffix(r.URL.Path, "/api.php"):
+			title := r.URL.Query().Get("titles")
+			page := map[string]any{"title": strings.ReplaceAll(title, "_", " ")}
+			for canonical, redirects := range stub.redirects {
+				if slices.Contains(redirects, title) {
+					page["title"] = strings.ReplaceAll(canonical, "_", " ")
+				}
+			}
+			if r.URL.Query().Get("prop") == "redirects" {
+				var redirects []map[string]any
+				for _, redirect := range stub.redirects[title] {
+					redirects = append(redirects, map[string]any{"ns": 0, "title": strings.ReplaceAll(redirect, "_", " ")})
+				}
+				page["redirects"] = redirects
+			}
+			json.NewEncoder(w).Encode(map[string]any{"query": map[string]any{"pages": []any{page}}})
+			return
+		}
+
+		// Per-article paths end with /{article}/{granularity}/{start}/{end}
+		segments := strings.Split(r.URL.Path, "/")
+		article := segments[len(segments)-4]
+		views, ok := stub.views[article]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		timestamps := stub.timestamps
+		if len(timestamps) == 0 {
+			timestamps = []string{"2024020100"}
+		}
+		var items []wikimedia.ArticleItem
+		for _, timestamp := range timestamps {
+			items = append(items, wikimedia.ArticleItem{Article: article, Timestamp: timestamp, Views: views})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"items": items})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// Serve a request to the List handler against server, returning the response
+func serveList(t *testing.T, server *httptest.Server, query string) *httptest.ResponseRecorder {
+	client := wikimedia.NewClient(server.URL, server.Client(), wikimedia.WithProjectUrl(server.URL+"/{project}/w"))
+	ph := NewPageviewsHandler(client, cache.NewMemoryCache(10))
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	e.GET("/pageviews", ph.List)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pageviews?"+query, nil))
+
+	return rec
+}
+
+func TestPageviewsHandler_List_SearchFallback(t *testing.T) {
+	server := newStubWikimedia(t, stubWikimedia{
+		views:  map[string]int32{"Michael_Phelps": 125860},
+		search: []wikimedia.SearchResult{{Key: "Michael_Phelps"}, {Key: "Michael_Phelps_II"}},
+	})
+
+	rec := serveList(t, server, "article=MICHAEL_phelps&date=202402")
+
+	var body apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusNotFound || body.Code != apierror.CodeArticleNotFound {
+		t.Fatalf("List for an unknown article responds %d %+v; Expected 404 %s", rec.Code, body, apierror.CodeArticleNotFound)
+	}
+
+	expectedSuggestions := []string{"Michael_Phelps", "Michael_Phelps_II"}
+	if !slices.Equal(body.Suggestions, expectedSuggestions) {
+		t.Errorf("List for an unknown article suggests %q; Expected %q", body.Suggestions, expectedSuggestions)
+	}
+
+	if !strings.Contains(body.Message, "Consider titlizing article param as Michael_phelps or Michael_Phelps") {
+		t.Errorf("List for an unknown article responds with message %q; Expected casing suggestions", body.Message)
+	}
+}
+
+func TestPageviewsHandler_List_SearchUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	rec := serveList(t, server, "article=Orca&date=202402")
+
+	var body apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusNotFound || body.Code != apierror.CodeArticleNotFound || len(body.Suggestions) != 0 {
+		t.Errorf("List with search unavailable responds %d %+v; Expected 404 %s without suggestions", rec.Code, body, apierror.CodeArticleNotFound)
+	}
+}
+
+func TestPageviewsHandler_List_Autocorrect(t *testing.T) {
+	server := newStubWikimedia(t, stubWikimedia{
+		views:  map[string]int32{"Michael_Phelps": 125860},
+		search: []wikimedia.SearchResult{{Key: "Michael_Phelps"}},
+	})
+
+	rec := serveList(t, server, "article=MICHAEL_phelps&date=202402&autocorrect=true")
+
+	var items []Item
+	json.Unmarshal(rec.Body.Bytes(), &items)
+	if rec.Code != http.StatusOK || len(items) != 1 || items[0].Article != "Michael_Phelps" {
+		t.Errorf("List with autocorrect responds %d %s; Expected 200 with items for Michael_Phelps", rec.Code, rec.Body)
+	}
+}
+
+func TestPageviewsHandler_List_Redirects(t *testing.T) {
+	server := newStubWikimedia(t, stubWikimedia{
+		views:     map[string]int32{"Michael_Phelps": 125860, "Michael_phelps": 40, "Phelps": 2},
+		redirects: map[string][]string{"Michael_Phelps": {"Michael_phelps", "Phelps", "MPhelps"}},
+	})
+
+	testCases := []struct {
+		query     string
+		article   string
+		canonical string
+		views     int32
+	}{
+		{"article=Michael_phelps&date=202402", "Michael_phelps", "", 40},
+		{"article=Michael_phelps&date=202402&resolve_redirects=true", "Michael_Phelps", "Michael_Phelps", 125860},
+		{"article=Michael_phelps&date=202402&sum_redirects=true", "Michael_Phelps", "Michael_Phelps", 125902},
+		{"article=Michael_Phelps&date=202402&sum_redirects=true", "Michael_Phelps", "Michael_Phelps", 125902},
+	}
+
+	for _, tc := range testCases {
+		rec := serveList(t, server, tc.query)
+
+		var items []Item
+		json.Unmarshal(rec.Body.Bytes(), &items)
+		if rec.Code != http.StatusOK || len(items) != 1 {
+			t.Errorf("List?%s responds %d %s; Expected 200 with one item", tc.query, rec.Code, rec.Body)
+			continue
+		}
+
+		if items[0].Article != tc.article || items[0].Canonical != tc.canonical || items[0].Views != tc.views {
+			t.Errorf("List?%s returns article %q, canonical %q, views %d; Expected %q, %q, %d", tc.query, items[0].Article, items[0].Canonical, items[0].Views, tc.article, tc.canonical, tc.views)
+		}
+	}
+}
+
+func TestPageviewsHandler_List_ForbiddenChars(t *testing.T) {
+	server := newStubWikimedia(t, stubWikimedia{views: map[string]int32{"Orca": 10}})
+
+	before := metricstest.ValidationFailures(t, "article", paramvalidator.ReasonForbiddenChars)
+	rec := serveList(t, server, "article=Orca%23&date=202402")
+	after := metricstest.ValidationFailures(t, "article", paramvalidator.ReasonForbiddenChars)
+
+	var body apierror.Error
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(body.Message, "article param Orca%23 is invalid") {
+		t.Errorf("List for article Orca# responds %d %q; Expected 400 for forbidden chars", rec.Code, body.Message)
+	}
+
+	if after-before != 1 {
+		t.Errorf("List for article Orca# counts %v forbidden_chars failures of the article param; Expected 1", after-before)
+	}
+}
+
+func TestPageviewsHandler_List_CSV(t *testing.T) {
+	server := newStubWikimedia(t, stubWikimedia{views: map[string]int32{"Are_You_the_One?": 2}})
+
+	rec := serveList(t, server, "article=Are_You_the_One%3F&date=202402&format=csv")
+
+	expected := "project,article,granularity,timestamp,access,agent,views,canonical\n,Are_You_the_One?,,2024020100,,,2,\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != expected {
+		t.Errorf("List with format=csv responds %d %q; Expected 200 %q", rec.Code, rec.Body, expected)
+	}
+}
+
+func TestPageviewsHandler_Query_Order(t *testing.T) {
+	server := newStubWikimedia(t, stubWikimedia{
+		views:      map[string]int32{"Orca": 10},
+		timestamps: []string{"2024020300", "2024020100", "2024020200"},
+	})
+	client := wikimedia.NewClient(server.URL, server.Client())
+	ph := NewPageviewsHandler(client, cache.NewMemoryCache(10))
+
+	params := Params{Granularity: "daily", Date: "202402"}
+	if err := params.Validate(context.Background()); err != nil {
+		t.Fatalf("Params.Validate returns %v; Expected nil", err)
+	}
+
+	// Once fetched from the Wikipedia API and once served from the cache
+	expected := []string{"2024020100", "2024020200", "2024020300"}
+	for i := 0; i < 2; i++ {
+		items, err := ph.Query(context.Background(), params, "Orca")
+		if err != nil {
+			t.Fatalf("Query returns %v; Expected nil", err)
+		}
+
+		var timestamps []string
+		for _, item := range items {
+			timestamps = append(timestamps, item.Timestamp)
+		}
+		if !slices.Equal(timestamps, expected) {
+			t.Errorf("Query returns items at %v; Expected %v", timestamps, expected)
+		}
+	}
+}
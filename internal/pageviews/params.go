@@ -0,0 +1,101 @@
+package pageviews
+
+import (
+	"context"
+	"time"
+	"wikiviews/internal/paramformatter"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/tracing"
+)
+
+// Params holds the query params shared by every article in a request
+type Params struct {
+	Project     string `json:"project" query:"project"`
+	Access      string `json:"access" query:"access"`
+	Agent       string `json:"agent" query:"agent"`
+	Granularity string `json:"granularity" query:"granularity"`
+	Date        string `json:"date" query:"date"`
+	Start       string `json:"start" query:"start"`
+	End         string `json:"end" query:"end"`
+	// Autocorrect retries an article with no results as the top title search candidate
+	Autocorrect bool `json:"autocorrect" query:"autocorrect"`
+	// ResolveRedirects queries the article a redirect points at, reporting it as each item's canonical title
+	ResolveRedirects bool `json:"resolve_redirects" query:"resolve_redirects"`
+	// SumRedirects resolves redirects and adds the views of every redirect to the canonical article onto it
+	SumRedirects bool `json:"sum_redirects" query:"sum_redirects"`
+
+	// Start and end of the range as sent to the Wikipedia API, set by Validate
+	rangeStart string
+	rangeEnd   string
+}
+
+// Validate params, filling in defaults and the range boundaries the Wikipedia API needs.
+// Date validation and range formatting are traced as children of any span in ctx
+func (p *Params) Validate(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "Params.Validate")
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
+	if err = paramvalidator.Project(&p.Project); err != nil {
+		return
+	}
+	if err = paramvalidator.Granularity(&p.Granularity, paramvalidator.NewGranularityValidator()); err != nil {
+		return
+	}
+	if err = paramvalidator.Access(&p.Access); err != nil {
+		return
+	}
+	if err = paramvalidator.Agent(&p.Agent); err != nil {
+		return
+	}
+
+	// Validate date input. A single date param is shorthand for a one-month start/end range
+	if len(p.Start) == 0 && len(p.End) == 0 {
+		dv := paramvalidator.NewDateValidator()
+		err = tracing.Run(ctx, "DateValidator.Run", func(context.Context) (err error) {
+			_, err = dv.Run(p.Date)
+			return
+		})
+		if err != nil {
+			return
+		}
+		p.Start, p.End = p.Date, p.Date
+	} else if len(p.Date) > 0 {
+		return paramvalidator.NewError("date", paramvalidator.ReasonInvalid, "error: date param is invalid: param cannot be combined with start or end params")
+	}
+
+	// Validate date range input
+	rv := paramvalidator.NewRangeValidator()
+	err = tracing.Run(ctx, "RangeValidator.Run", func(context.Context) (err error) {
+		_, err = rv.Run(p.Start, p.End, p.Granularity)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	// Return start and end date params that Wikipedia API needs from the date range input
+	rf := paramformatter.NewRangeFormatter()
+	err = tracing.Run(ctx, "RangeFormatter.Run", func(context.Context) (err error) {
+		p.rangeStart, p.rangeEnd, err = rf.Run(p.Start, p.End, p.Granularity)
+		return
+	})
+	return
+}
+
+// Return the first instant after the validated range
+func (p *Params) periodEnd() time.Time {
+	if len(p.rangeEnd) == len("2006010215") {
+		end, _ := time.Parse("2006010215", p.rangeEnd)
+		return end.Add(time.Hour)
+	}
+
+	end, _ := time.Parse("20060102", p.rangeEnd)
+	return end.AddDate(0, 0, 1)
+}
+
+func (p *Params) resolvesRedirects() bool {
+	return p.ResolveRedirects || p.SumRedirects
+}
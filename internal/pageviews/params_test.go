@@ -0,0 +1,40 @@
+package pageviews
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestParams_Validate_Spans(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	testCases := []struct {
+		params   Params
+		expected []string
+	}{
+		{Params{Start: "202401", End: "202403"}, []string{"RangeValidator.Run", "RangeFormatter.Run", "Params.Validate"}},
+		{Params{Date: "202402"}, []string{"DateValidator.Run", "RangeValidator.Run", "RangeFormatter.Run", "Params.Validate"}},
+	}
+
+	for _, tc := range testCases {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		if err := tc.params.Validate(context.Background()); err != nil {
+			t.Fatalf("Params.Validate for %+v returns %v; Expected nil", tc.params, err)
+		}
+
+		var names []string
+		for _, span := range recorder.Ended() {
+			names = append(names, span.Name())
+		}
+		if !slices.Equal(names, tc.expected) {
+			t.Errorf("Params.Validate for %+v records spans %v; Expected %v", tc.params, names, tc.expected)
+		}
+	}
+}
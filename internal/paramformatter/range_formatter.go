@@ -0,0 +1,71 @@
+package paramformatter
+
+import "fmt"
+
+type RangeFormatter struct {
+	df *DateFormatter
+}
+
+// Run maps a validated start and end param onto the boundaries the Wikipedia API expects for a granularity:
+// - monthly: the first day of the start month and the last day of the end month, as YYYYMMDD
+// - daily: the start and end days as YYYYMMDD, widening months to their first and last day
+// - hourly: the start and end hours as YYYYMMDDHH, widening days to their first and last hour
+func (rf *RangeFormatter) Run(start, end, granularity string) (rangeStart, rangeEnd string, err error) {
+	switch granularity {
+	case "monthly":
+		rangeStart, _, err = rf.df.Run(start[:6])
+		if err != nil {
+			return
+		}
+
+		_, rangeEnd, err = rf.df.Run(end[:6])
+	case "daily":
+		if rangeStart, err = rf.firstDay(start); err != nil {
+			return
+		}
+
+		rangeEnd, err = rf.lastDay(end)
+	case "hourly":
+		if rangeStart, err = rf.firstDay(start); err != nil {
+			return
+		}
+		if len(rangeStart) == 8 {
+			rangeStart += "00"
+		}
+
+		if rangeEnd, err = rf.lastDay(end); err != nil {
+			return
+		}
+		if len(rangeEnd) == 8 {
+			rangeEnd += "23"
+		}
+	default:
+		err = fmt.Errorf("error: granularity %s is not supported", granularity)
+	}
+
+	return
+}
+
+// Widen a YYYYMM param to the first day of its month, leaving finer params as they are
+func (rf *RangeFormatter) firstDay(date string) (day string, err error) {
+	if len(date) != 6 {
+		return date, nil
+	}
+
+	day, _, err = rf.df.Run(date)
+	return
+}
+
+// Widen a YYYYMM param to the last day of its month, leaving finer params as they are
+func (rf *RangeFormatter) lastDay(date string) (day string, err error) {
+	if len(date) != 6 {
+		return date, nil
+	}
+
+	_, day, err = rf.df.Run(date)
+	return
+}
+
+func NewRangeFormatter() *RangeFormatter {
+	return &RangeFormatter{df: NewDateFormatter()}
+}
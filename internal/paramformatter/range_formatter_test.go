@@ -0,0 +1,39 @@
+package paramformatter
+
+import "testing"
+
+func TestRangeFormatter_Run(t *testing.T) {
+	formatter := NewRangeFormatter()
+
+	testCases := []struct {
+		start         string
+		end           string
+		granularity   string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{"202301", "202301", "monthly", "20230101", "20230131"},
+		{"202201", "202312", "monthly", "20220101", "20231231"},
+		{"202311", "202402", "monthly", "20231101", "20240229"},
+		{"20230115", "20230210", "monthly", "20230101", "20230228"},
+		{"202304", "20230405", "monthly", "20230401", "20230430"},
+		{"20230115", "20230210", "daily", "20230115", "20230210"},
+		{"202402", "202402", "daily", "20240201", "20240229"},
+		{"202304", "20230405", "daily", "20230401", "20230405"},
+		{"2024010100", "2024010123", "hourly", "2024010100", "2024010123"},
+		{"20240101", "20240102", "hourly", "2024010100", "2024010223"},
+		{"202302", "202302", "hourly", "2023020100", "2023022823"},
+	}
+
+	for _, tc := range testCases {
+		actualStart, actualEnd, _ := formatter.Run(tc.start, tc.end, tc.granularity)
+
+		if actualStart != tc.expectedStart {
+			t.Errorf("TestRangeFormatter.Run(%q, %q, %q) returns start date %q; Expected %q", tc.start, tc.end, tc.granularity, actualStart, tc.expectedStart)
+		}
+
+		if actualEnd != tc.expectedEnd {
+			t.Errorf("TestRangeFormatter.Run(%q, %q, %q) returns end date %q; Expected %q", tc.start, tc.end, tc.granularity, actualEnd, tc.expectedEnd)
+		}
+	}
+}
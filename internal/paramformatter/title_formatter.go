@@ -1,7 +1,6 @@
 package paramformatter
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 
@@ -9,7 +8,9 @@ import (
 	"golang.org/x/text/language"
 )
 
-type TitleFormatter struct{}
+type TitleFormatter struct {
+	project string
+}
 
 const alwaysLowerWord = "a an and in of on the to"
 
@@ -45,14 +46,34 @@ func (tf *TitleFormatter) Run(param string, firstWordOnly bool) string {
 			words[i] = c.String(strings.ToLower(w))
 		}
 	}
-	fmt.Println(words)
 	return strings.Join(words, "_")
 }
 
+// Suggestions returns alternative casings of a title to try when the Wikipedia API finds no results for it
+func (tf *TitleFormatter) Suggestions(param string) (suggestions []string) {
+	// Wiktionary titles are case-sensitive and most entries begin lower case
+	if strings.HasSuffix(tf.project, ".wiktionary.org") {
+		suggestions = append(suggestions, strings.ToLower(param))
+	}
+
+	if tf.IsSingleWord(param) {
+		suggestions = append(suggestions, tf.Run(param, true))
+	} else if tf.IsMultiWord(param) {
+		suggestions = append(suggestions, tf.Run(param, true), tf.Run(param, false))
+	}
+
+	return
+}
+
 func (tf *TitleFormatter) isAlwaysLowerWord(word string) bool {
+	// Small words are only kept lower case under English title casing rules
+	if !strings.HasPrefix(tf.project, "en.") {
+		return false
+	}
+
 	return strings.Contains(alwaysLowerWord, " "+word+" ")
 }
 
-func NewTitleFormatter() *TitleFormatter {
-	return &TitleFormatter{}
+func NewTitleFormatter(project string) *TitleFormatter {
+	return &TitleFormatter{project: project}
 }
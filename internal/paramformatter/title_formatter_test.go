@@ -1,9 +1,12 @@
 package paramformatter
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestTitleFormatter_Run(t *testing.T) {
-	formatter := NewTitleFormatter()
+	formatter := NewTitleFormatter("en.wikipedia.org")
 
 	testCases := []struct {
 		param         string
@@ -49,7 +52,7 @@ func TestTitleFormatter_Run(t *testing.T) {
 }
 
 func TestTitleFormatter_IsSingleWord(t *testing.T) {
-	formatter := NewTitleFormatter()
+	formatter := NewTitleFormatter("en.wikipedia.org")
 
 	testCases := []struct {
 		param          string
@@ -73,7 +76,7 @@ func TestTitleFormatter_IsSingleWord(t *testing.T) {
 }
 
 func TestTitleFormatter_IsMultiWord(t *testing.T) {
-	formatter := NewTitleFormatter()
+	formatter := NewTitleFormatter("en.wikipedia.org")
 
 	testCases := []struct {
 		param          string
@@ -96,3 +99,48 @@ func TestTitleFormatter_IsMultiWord(t *testing.T) {
 		}
 	}
 }
+
+func TestTitleFormatter_Run_NonEnglish(t *testing.T) {
+	formatter := NewTitleFormatter("de.wikipedia.org")
+
+	testCases := []struct {
+		param         string
+		firstWordOnly bool
+		newParam      string
+	}{
+		{"call_of_the_wild", false, "Call_Of_The_Wild"},
+		{"call_of_the_wild", true, "Call_of_the_wild"},
+		{"BERLINER_MAUER", false, "Berliner_Mauer"},
+	}
+
+	for _, tc := range testCases {
+		actualNewParam := formatter.Run(tc.param, tc.firstWordOnly)
+
+		if actualNewParam != tc.newParam {
+			t.Errorf("TestTitleFormatter.Run(%q) returns new param %q; Expected %q", tc.param, actualNewParam, tc.newParam)
+		}
+	}
+}
+
+func TestTitleFormatter_Suggestions(t *testing.T) {
+	testCases := []struct {
+		project     string
+		param       string
+		suggestions []string
+	}{
+		{"en.wikipedia.org", "orca", []string{"Orca"}},
+		{"en.wikipedia.org", "MICHAEL_Phelps", []string{"Michael_phelps", "Michael_Phelps"}},
+		{"en.wikipedia.org", "!!!", nil},
+		{"en.wiktionary.org", "Dog", []string{"dog", "Dog"}},
+		{"en.wiktionary.org", "A_PRIORI", []string{"a_priori", "A_priori", "A_Priori"}},
+	}
+
+	for _, tc := range testCases {
+		formatter := NewTitleFormatter(tc.project)
+		actualSuggestions := formatter.Suggestions(tc.param)
+
+		if !slices.Equal(actualSuggestions, tc.suggestions) {
+			t.Errorf("TestTitleFormatter.Suggestions(%q) for project %q returns %q; Expected %q", tc.param, tc.project, actualSuggestions, tc.suggestions)
+		}
+	}
+}
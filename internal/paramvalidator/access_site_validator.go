@@ -0,0 +1,13 @@
+package paramvalidator
+
+type AccessSiteValidator struct{}
+
+var accessSites = []string{"all-sites", "desktop-site", "mobile-site"}
+
+func (asv *AccessSiteValidator) Run(accessSite string) (isValid bool, err error) {
+	return oneOf("access_site", accessSite, accessSites)
+}
+
+func NewAccessSiteValidator() *AccessSiteValidator {
+	return &AccessSiteValidator{}
+}
@@ -0,0 +1,29 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestAccessSiteValidator_Run(t *testing.T) {
+	validator := NewAccessSiteValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"all-sites", true},
+		{"desktop-site", true},
+		{"mobile-site", true},
+		{"", false},
+		{"all-access", false},
+		{"mobile-app", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestAccessSiteValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package paramvalidator
+
+type AccessValidator struct{}
+
+var accessMethods = []string{"all-access", "desktop", "mobile-app", "mobile-web"}
+
+func (av *AccessValidator) Run(access string) (isValid bool, err error) {
+	return oneOf("access", access, accessMethods)
+}
+
+func NewAccessValidator() *AccessValidator {
+	return &AccessValidator{}
+}
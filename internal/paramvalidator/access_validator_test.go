@@ -0,0 +1,30 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestAccessValidator_Run(t *testing.T) {
+	validator := NewAccessValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"all-access", true},
+		{"desktop", true},
+		{"mobile-app", true},
+		{"mobile-web", true},
+		{"", false},
+		{"mobile", false},
+		{"Desktop", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestAccessValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
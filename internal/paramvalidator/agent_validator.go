@@ -0,0 +1,13 @@
+package paramvalidator
+
+type AgentValidator struct{}
+
+var agentTypes = []string{"all-agents", "user", "spider", "automated"}
+
+func (av *AgentValidator) Run(agent string) (isValid bool, err error) {
+	return oneOf("agent", agent, agentTypes)
+}
+
+func NewAgentValidator() *AgentValidator {
+	return &AgentValidator{}
+}
@@ -0,0 +1,30 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestAgentValidator_Run(t *testing.T) {
+	validator := NewAgentValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"all-agents", true},
+		{"user", true},
+		{"spider", true},
+		{"automated", true},
+		{"", false},
+		{"bot", false},
+		{"all-access", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestAgentValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
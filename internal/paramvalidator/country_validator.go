@@ -0,0 +1,29 @@
+package paramvalidator
+
+import (
+	"regexp"
+)
+
+type CountryValidator struct{}
+
+// ISO 3166-1 alpha-2 country code, as used by the Wikipedia API, e.g. US or DE
+const countryCode = `^[A-Z]{2}$`
+
+func (cv *CountryValidator) Run(country string) (isValid bool, err error) {
+	if len(country) == 0 {
+		err = NewError("country", ReasonEmpty, "error: country param is invalid: param cannot be empty")
+		return
+	}
+
+	re := regexp.MustCompile(countryCode)
+	if !re.MatchString(country) {
+		err = NewError("country", ReasonInvalid, "error: country param %s is invalid: please enter a two-letter ISO 3166-1 country code in upper case, e.g. US", country)
+		return
+	}
+
+	return true, nil
+}
+
+func NewCountryValidator() *CountryValidator {
+	return &CountryValidator{}
+}
@@ -0,0 +1,30 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestCountryValidator_Run(t *testing.T) {
+	validator := NewCountryValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"US", true},
+		{"DE", true},
+		{"us", false},
+		{"USA", false},
+		{"U", false},
+		{"U1", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestCountryValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package paramvalidator
 
 import (
-	"fmt"
 	"regexp"
 )
 
@@ -11,13 +10,13 @@ const yearMonth = `^[12]\d{3}(0[1-9]|1[0-2])$`
 
 func (dv *DateValidator) Run(date string) (isValid bool, err error) {
 	if len(date) == 0 {
-		err = fmt.Errorf("error: date param is invalid: param cannot be empty. Please enter in form YYYYMM")
+		err = NewError("date", ReasonEmpty, "error: date param is invalid: param cannot be empty. Please enter in form YYYYMM")
 		return
 	}
 
 	re := regexp.MustCompile(yearMonth)
 	if !re.MatchString(date) {
-		err = fmt.Errorf("error: date param is invalid: please enter a valid year and month in form YYYYMM")
+		err = NewError("date", ReasonBadDate, "error: date param is invalid: please enter a valid year and month in form YYYYMM")
 		return
 	}
 
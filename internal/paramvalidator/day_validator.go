@@ -0,0 +1,28 @@
+package paramvalidator
+
+import (
+	"regexp"
+	"time"
+)
+
+type DayValidator struct{}
+
+func (dv *DayValidator) Run(date string) (isValid bool, err error) {
+	if len(date) == 0 {
+		err = NewError("date", ReasonEmpty, "error: date param is invalid: param cannot be empty. Please enter in form YYYYMMDD")
+		return
+	}
+
+	// Catch days that do not exist in their month, e.g. 20230230
+	re := regexp.MustCompile(yearMonthDay)
+	if _, parseErr := time.Parse("20060102", date); !re.MatchString(date) || parseErr != nil {
+		err = NewError("date", ReasonBadDate, "error: date param is invalid: please enter a valid year, month and day in form YYYYMMDD")
+		return
+	}
+
+	return true, nil
+}
+
+func NewDayValidator() *DayValidator {
+	return &DayValidator{}
+}
@@ -0,0 +1,32 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestDayValidator_Run(t *testing.T) {
+	validator := NewDayValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"20240101", true},
+		{"20240229", true},
+		{"20230229", false},
+		{"20240431", false},
+		{"20241301", false},
+		{"30240101", false},
+		{"202401", false},
+		{"2024010100", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestDayValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
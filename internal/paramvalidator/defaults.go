@@ -0,0 +1,48 @@
+package paramvalidator
+
+// Values filled in for params shared across endpoints when they are left empty
+const (
+	// English-language Wikipedia
+	DefaultProject = "en.wikipedia.org"
+	// Monthly data points
+	DefaultGranularity = "monthly"
+	// All traffic, whatever the site or client
+	DefaultAccess = "all-access"
+	DefaultAgent  = "all-agents"
+)
+
+// Validator checks a single param, as every validator of this package does
+type Validator interface {
+	Run(param string) (isValid bool, err error)
+}
+
+// OrDefault fills in param with value when it is empty, then checks it with v
+func OrDefault(param *string, value string, v Validator) (err error) {
+	if len(*param) == 0 {
+		*param = value
+	}
+
+	_, err = v.Run(*param)
+	return
+}
+
+// Project checks a project param, defaulting to DefaultProject
+func Project(project *string) error {
+	return OrDefault(project, DefaultProject, NewProjectValidator())
+}
+
+// Granularity checks a granularity param with gv, which decides whether hourly data points are supported,
+// defaulting to DefaultGranularity
+func Granularity(granularity *string, gv Validator) error {
+	return OrDefault(granularity, DefaultGranularity, gv)
+}
+
+// Access checks an access filter, defaulting to DefaultAccess
+func Access(access *string) error {
+	return OrDefault(access, DefaultAccess, NewAccessValidator())
+}
+
+// Agent checks an agent filter, defaulting to DefaultAgent
+func Agent(agent *string) error {
+	return OrDefault(agent, DefaultAgent, NewAgentValidator())
+}
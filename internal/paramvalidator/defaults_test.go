@@ -0,0 +1,26 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestOrDefault(t *testing.T) {
+	testCases := []struct {
+		param    string
+		expected string
+		isValid  bool
+	}{
+		{"", DefaultAccess, true},
+		{"desktop", "desktop", true},
+		{"phone", "phone", false},
+	}
+
+	for _, tc := range testCases {
+		param := tc.param
+		err := OrDefault(&param, DefaultAccess, NewAccessValidator())
+
+		if param != tc.expected || (err == nil) != tc.isValid {
+			t.Errorf("OrDefault(%q) sets %q, err = %v; Expected %q with isValid = %t", tc.param, param, err, tc.expected, tc.isValid)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package paramvalidator
+
+type EditorTypeValidator struct{}
+
+var editorTypes = []string{"all-editor-types", "anonymous", "group-bot", "name-bot", "user"}
+
+func (etv *EditorTypeValidator) Run(editorType string) (isValid bool, err error) {
+	return oneOf("editor_type", editorType, editorTypes)
+}
+
+func NewEditorTypeValidator() *EditorTypeValidator {
+	return &EditorTypeValidator{}
+}
@@ -0,0 +1,30 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestEditorTypeValidator_Run(t *testing.T) {
+	validator := NewEditorTypeValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"all-editor-types", true},
+		{"anonymous", true},
+		{"group-bot", true},
+		{"name-bot", true},
+		{"user", true},
+		{"", false},
+		{"bot", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestEditorTypeValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package paramvalidator
+
+import (
+	"slices"
+	"strings"
+)
+
+// Check a param is one of a fixed set of values, e.g. the granularities supported by the Wikipedia API
+func oneOf(name, param string, values []string) (isValid bool, err error) {
+	if len(param) == 0 {
+		err = NewError(name, ReasonEmpty, "error: %s param is invalid: param cannot be empty", name)
+		return
+	}
+
+	if !slices.Contains(values, param) {
+		err = NewError(name, ReasonInvalid, "error: %s param %s is invalid: param must be one of %s", name, param, strings.Join(values, ", "))
+		return
+	}
+
+	return true, nil
+}
@@ -0,0 +1,30 @@
+package paramvalidator
+
+import "fmt"
+
+// Reasons a param fails validation, few enough to label metrics with
+const (
+	ReasonEmpty          = "empty"
+	ReasonLowercase      = "lowercase"
+	ReasonForbiddenChars = "forbidden_chars"
+	ReasonBadDate        = "bad_date"
+	ReasonInvalid        = "invalid"
+)
+
+// Error is returned by every validator, naming the param that failed and why alongside the message for clients
+type Error struct {
+	// Param is the name of the param that failed, e.g. article, or date for a date range
+	Param string
+	// Reason is one of the Reason constants
+	Reason  string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError returns an Error for param failing for reason, with a message formatted from format and args
+func NewError(param, reason, format string, args ...any) *Error {
+	return &Error{Param: param, Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
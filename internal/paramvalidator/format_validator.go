@@ -0,0 +1,13 @@
+package paramvalidator
+
+type FormatValidator struct{}
+
+var formats = []string{"json", "csv", "tsv", "ndjson"}
+
+func (fv *FormatValidator) Run(format string) (isValid bool, err error) {
+	return oneOf("format", format, formats)
+}
+
+func NewFormatValidator() *FormatValidator {
+	return &FormatValidator{}
+}
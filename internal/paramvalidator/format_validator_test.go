@@ -0,0 +1,30 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestFormatValidator_Run(t *testing.T) {
+	validator := NewFormatValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"json", true},
+		{"csv", true},
+		{"tsv", true},
+		{"ndjson", true},
+		{"", false},
+		{"CSV", false},
+		{"xml", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestFormatValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package paramvalidator
+
+type GranularityValidator struct{}
+
+var granularities = []string{"monthly", "daily", "hourly"}
+
+func (gv *GranularityValidator) Run(granularity string) (isValid bool, err error) {
+	return oneOf("granularity", granularity, granularities)
+}
+
+func NewGranularityValidator() *GranularityValidator {
+	return &GranularityValidator{}
+}
+
+// DailyGranularityValidator checks the granularity of metrics the Wikipedia API reports no finer than daily,
+// e.g. unique devices and edits
+type DailyGranularityValidator struct{}
+
+var dailyGranularities = []string{"monthly", "daily"}
+
+func (gv *DailyGranularityValidator) Run(granularity string) (isValid bool, err error) {
+	return oneOf("granularity", granularity, dailyGranularities)
+}
+
+func NewDailyGranularityValidator() *DailyGranularityValidator {
+	return &DailyGranularityValidator{}
+}
@@ -0,0 +1,52 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestGranularityValidator_Run(t *testing.T) {
+	validator := NewGranularityValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"monthly", true},
+		{"daily", true},
+		{"hourly", true},
+		{"", false},
+		{"Monthly", false},
+		{"weekly", false},
+		{"yearly", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestGranularityValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
+
+func TestDailyGranularityValidator_Run(t *testing.T) {
+	validator := NewDailyGranularityValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"monthly", true},
+		{"daily", true},
+		{"hourly", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestDailyGranularityValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
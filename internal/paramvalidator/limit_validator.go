@@ -0,0 +1,28 @@
+package paramvalidator
+
+import (
+	"strconv"
+)
+
+type LimitValidator struct {
+	max int
+}
+
+func (lv *LimitValidator) Run(limit string) (isValid bool, err error) {
+	if len(limit) == 0 {
+		err = NewError("limit", ReasonEmpty, "error: limit param is invalid: param cannot be empty")
+		return
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 1 || n > lv.max {
+		err = NewError("limit", ReasonInvalid, "error: limit param %s is invalid: param must be a number from 1 to %d", limit, lv.max)
+		return
+	}
+
+	return true, nil
+}
+
+func NewLimitValidator(max int) *LimitValidator {
+	return &LimitValidator{max: max}
+}
@@ -0,0 +1,31 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestLimitValidator_Run(t *testing.T) {
+	validator := NewLimitValidator(1000)
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"1", true},
+		{"100", true},
+		{"1000", true},
+		{"1001", false},
+		{"0", false},
+		{"-5", false},
+		{"ten", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestLimitValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
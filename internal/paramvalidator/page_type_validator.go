@@ -0,0 +1,13 @@
+package paramvalidator
+
+type PageTypeValidator struct{}
+
+var pageTypes = []string{"all-page-types", "content", "non-content"}
+
+func (ptv *PageTypeValidator) Run(pageType string) (isValid bool, err error) {
+	return oneOf("page_type", pageType, pageTypes)
+}
+
+func NewPageTypeValidator() *PageTypeValidator {
+	return &PageTypeValidator{}
+}
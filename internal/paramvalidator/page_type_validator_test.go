@@ -0,0 +1,28 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestPageTypeValidator_Run(t *testing.T) {
+	validator := NewPageTypeValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"all-page-types", true},
+		{"content", true},
+		{"non-content", true},
+		{"", false},
+		{"article", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestPageTypeValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestProjectValidator_Run(t *testing.T) {
+	validator := NewProjectValidator()
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"en.wikipedia.org", true},
+		{"de.wikipedia.org", true},
+		{"ja.wikipedia.org", true},
+		{"fr.wiktionary.org", true},
+		{"en.wikivoyage.org", true},
+		{"zh-min-nan.wikipedia.org", true},
+		{"simple.wikipedia.org", true},
+		{"simple.wiktionary.org", true},
+		{"test.wikipedia.org", true},
+		{"commons.wikimedia.org", true},
+		{"www.wikidata.org", true},
+		{"", false},
+		{"en.wikipedia", false},
+		{"wikipedia.org", false},
+		{"en.example.org", false},
+		{"zz.wikipedia.org", false},
+		{"en.wikimedia.org", false},
+		{"EN.wikipedia.org", false},
+		{"en.wikipedia.org/wiki", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestProjectValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package paramvalidator
+
+import (
+	"regexp"
+	"time"
+)
+
+type RangeValidator struct{}
+
+const (
+	yearMonthDay     = `^[12]\d{3}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])$`
+	yearMonthDayHour = `^[12]\d{3}(0[1-9]|1[0-2])(0[1-9]|[12]\d|3[01])([01]\d|2[0-3])$`
+)
+
+// Date forms accepted for start and end params per granularity. Coarser forms are widened to whole periods
+var rangeForms = map[string]string{
+	"monthly": "YYYYMM or YYYYMMDD",
+	"daily":   "YYYYMM or YYYYMMDD",
+	"hourly":  "YYYYMM, YYYYMMDD or YYYYMMDDHH",
+}
+
+// Longest range a single request may ask for per granularity, so e.g. ten years of hourly data is rejected
+var rangeLimits = map[string]struct {
+	span        time.Duration
+	description string
+}{
+	"daily":  {366 * 24 * time.Hour, "366 days"},
+	"hourly": {31 * 24 * time.Hour, "31 days"},
+}
+
+func (rv *RangeValidator) Run(start, end, granularity string) (isValid bool, err error) {
+	startFrom, _, err := rv.parse("start", start, granularity)
+	if err != nil {
+		return
+	}
+
+	_, endTo, err := rv.parse("end", end, granularity)
+	if err != nil {
+		return
+	}
+
+	// Check the range runs forwards in time, allowing a start inside a coarser end, e.g. 20240115 to 202401
+	if !startFrom.Before(endTo) {
+		err = NewError("date", ReasonBadDate, "error: date range is invalid: start %s must not be after end %s", start, end)
+		return
+	}
+
+	// Check the range is within the limit for its granularity
+	if limit, ok := rangeLimits[granularity]; ok && endTo.Sub(startFrom) > limit.span {
+		err = NewError("date", ReasonBadDate, "error: date range is invalid: %s granularity supports at most %s per request", granularity, limit.description)
+		return
+	}
+
+	return true, nil
+}
+
+// Parse a start or end param into the period it covers, from its first instant up to the first instant after it
+func (rv *RangeValidator) parse(name, param, granularity string) (from, to time.Time, err error) {
+	forms, ok := rangeForms[granularity]
+	if !ok {
+		err = NewError("granularity", ReasonInvalid, "error: granularity param %s is invalid", granularity)
+		return
+	}
+
+	if len(param) == 0 {
+		err = NewError(name, ReasonEmpty, "error: %s param is invalid: param cannot be empty. Please enter in form %s", name, forms)
+		return
+	}
+
+	invalid := NewError(name, ReasonBadDate, "error: %s param is invalid: please enter a valid date in form %s", name, forms)
+
+	switch {
+	case regexp.MustCompile(yearMonth).MatchString(param):
+		from, err = time.Parse("200601", param)
+		to = from.AddDate(0, 1, 0)
+	case regexp.MustCompile(yearMonthDay).MatchString(param):
+		// Catch days that do not exist in their month, e.g. 20230230
+		from, err = time.Parse("20060102", param)
+		to = from.AddDate(0, 0, 1)
+	case granularity == "hourly" && regexp.MustCompile(yearMonthDayHour).MatchString(param):
+		from, err = time.Parse("2006010215", param)
+		to = from.Add(time.Hour)
+	default:
+		err = invalid
+	}
+
+	if err != nil {
+		err = invalid
+	}
+	return
+}
+
+func NewRangeValidator() *RangeValidator {
+	return &RangeValidator{}
+}
@@ -0,0 +1,58 @@
+package paramvalidator
+
+import (
+	"testing"
+)
+
+func TestRangeValidator_Run(t *testing.T) {
+	validator := NewRangeValidator()
+
+	testCases := []struct {
+		start       string
+		end         string
+		granularity string
+		isValid     bool
+	}{
+		{"202201", "202312", "monthly", true},
+		{"202401", "202401", "monthly", true},
+		{"20240115", "20240220", "monthly", true},
+		{"202401", "20240220", "monthly", true},
+		{"20240115", "202401", "monthly", true},
+		{"20240115", "202312", "monthly", false},
+		{"20240229", "20240229", "monthly", true},
+		{"2015070100", "2015070123", "monthly", false},
+		{"20230229", "202303", "monthly", false},
+		{"20240132", "202402", "monthly", false},
+		{"202312", "202201", "monthly", false},
+		{"20240220", "20240115", "monthly", false},
+		{"", "202401", "monthly", false},
+		{"202401", "", "monthly", false},
+		{"2024", "202401", "monthly", false},
+		{"302401", "302402", "monthly", false},
+		{"202413", "202414", "monthly", false},
+		{"20240101", "20240131", "daily", true},
+		{"202401", "202412", "daily", true},
+		{"20230101", "20231231", "daily", true},
+		{"20230101", "20240102", "daily", false},
+		{"202301", "202401", "daily", false},
+		{"2024010100", "2024010123", "daily", false},
+		{"2024010100", "2024010123", "hourly", true},
+		{"20240101", "20240131", "hourly", true},
+		{"202402", "202402", "hourly", true},
+		{"2024010100", "2024020100", "hourly", false},
+		{"202401", "202402", "hourly", false},
+		{"2024010124", "2024010124", "hourly", false},
+		{"2024010105", "2024010104", "hourly", false},
+		{"2024010105", "20240101", "hourly", true},
+		{"2024010200", "20240101", "hourly", false},
+		{"202401", "202401", "weekly", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.start, tc.end, tc.granularity)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestRangeValidator.Run(%q, %q, %q) returns isValid = %t; Expected %t", tc.start, tc.end, tc.granularity, isValid, tc.isValid)
+		}
+	}
+}
@@ -1,41 +1,47 @@
 package paramvalidator
 
 import (
-	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 )
 
 const forbiddenChars = "#<>[]{}|"
 
-type TitleValidator struct{}
+type TitleValidator struct {
+	project string
+}
 
 func (tv *TitleValidator) Run(param string) (isValid bool, err error) {
 	// Check for an empty param
 	if len(param) == 0 {
-		err = fmt.Errorf("error: article param is invalid: param cannot be empty")
+		err = NewError("article", ReasonEmpty, "error: article param is invalid: param cannot be empty")
 		return
 	}
 
-	// Check for a lower-case first character
+	// Check for a lower-case first character. Wiktionary titles are case-sensitive and may legitimately begin lower case
 	alphaRe := regexp.MustCompile(`^[[:alpha:]]$`)
 	firstChar := string(param[0])
 
-	if alphaRe.MatchString(firstChar) && strings.ToLower(firstChar) == firstChar {
-		err = fmt.Errorf("error: article param %s is invalid: param must not begin with a lower case character", param)
+	if !tv.isCaseSensitive() && alphaRe.MatchString(firstChar) && strings.ToLower(firstChar) == firstChar {
+		err = NewError("article", ReasonLowercase, "error: article param %s is invalid: param must not begin with a lower case character", url.QueryEscape(param))
 		return
 	}
 
 	// Check for forbidden characters
 	forbiddenRe := regexp.MustCompile(`.*(\#|\<|\>|\[|\]|\{|\}|\|)+.*`)
 	if forbiddenRe.MatchString(param) {
-		err = fmt.Errorf("error: article param %s is invalid: param must not contain chars %s", param, forbiddenChars)
+		err = NewError("article", ReasonForbiddenChars, "error: article param %s is invalid: param must not contain chars %s", url.QueryEscape(param), forbiddenChars)
 		return
 	}
 
 	return true, nil
 }
 
-func NewTitleValidator() *TitleValidator {
-	return &TitleValidator{}
+func (tv *TitleValidator) isCaseSensitive() bool {
+	return strings.HasSuffix(tv.project, ".wiktionary.org")
+}
+
+func NewTitleValidator(project string) *TitleValidator {
+	return &TitleValidator{project: project}
 }
@@ -5,7 +5,7 @@ import (
 )
 
 func TestTitleValidator_Run(t *testing.T) {
-	validator := NewTitleValidator()
+	validator := NewTitleValidator("en.wikipedia.org")
 
 	testCases := []struct {
 		param   string
@@ -35,3 +35,26 @@ func TestTitleValidator_Run(t *testing.T) {
 		}
 	}
 }
+
+func TestTitleValidator_Run_Wiktionary(t *testing.T) {
+	validator := NewTitleValidator("en.wiktionary.org")
+
+	testCases := []struct {
+		param   string
+		isValid bool
+	}{
+		{"abc", true},
+		{"Abc", true},
+		{"a_priori", true},
+		{"a#bc", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		isValid, _ := validator.Run(tc.param)
+
+		if isValid != tc.isValid {
+			t.Errorf("TestTitleValidator.Run(%q) returns isValid = %t; Expected %t", tc.param, isValid, tc.isValid)
+		}
+	}
+}
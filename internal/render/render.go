@@ -0,0 +1,272 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"wikiviews/internal/paramvalidator"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Format is an output format for a list of items
+type Format string
+
+const (
+	JSON   Format = "json"
+	CSV    Format = "csv"
+	TSV    Format = "tsv"
+	NDJSON Format = "ndjson"
+	// Table aligns columns for reading in a terminal. It is only written by Write, not served over HTTP
+	Table Format = "table"
+)
+
+// Media types of each format, as matched against the Accept header
+var mediaTypes = map[string]Format{
+	echo.MIMEApplicationJSON:    JSON,
+	"text/csv":                  CSV,
+	"text/tab-separated-values": TSV,
+	"application/x-ndjson":      NDJSON,
+	"application/ndjson":        NDJSON,
+}
+
+var contentTypes = map[Format]string{
+	JSON:   echo.MIMEApplicationJSONCharsetUTF8,
+	CSV:    "text/csv; charset=UTF-8",
+	TSV:    "text/tab-separated-values; charset=UTF-8",
+	NDJSON: "application/x-ndjson",
+}
+
+// Negotiate returns the format a request asks for, from the format param if set or else the Accept header.
+// A request that names no supported media type gets JSON
+func Negotiate(c echo.Context) (format Format, err error) {
+	if param := c.QueryParam("format"); len(param) > 0 {
+		fv := paramvalidator.NewFormatValidator()
+		if _, err = fv.Run(param); err != nil {
+			return
+		}
+		return Format(param), nil
+	}
+
+	// Take the first supported media type in the order listed, ignoring q-values
+	for _, accepted := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, parseErr := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if parseErr != nil {
+			continue
+		}
+		if format, ok := mediaTypes[mediaType]; ok {
+			return format, nil
+		}
+	}
+
+	return JSON, nil
+}
+
+// Items writes a 200 response with items in format
+func Items[T any](c echo.Context, format Format, items []T) error {
+	return writeAll(NewStream[T](c, format), items)
+}
+
+// Write writes items in format to w, e.g. to standard output. It also accepts Table, which has no media type
+func Write[T any](w io.Writer, format Format, items []T) error {
+	return writeAll(&Stream[T]{w: w, format: format}, items)
+}
+
+func writeAll[T any](stream *Stream[T], items []T) error {
+	for _, item := range items {
+		if err := stream.Write(item); err != nil {
+			return err
+		}
+	}
+
+	return stream.Close()
+}
+
+// Stream writes a 200 response of items of struct type T one at a time, flushing each to the client as it is
+// written. Delimited formats have a header row with a column per JSON field of T, in field order, so columns
+// stay stable however items are filled in. Nothing is sent until the first Write or Close, so a handler may
+// still respond with an error until then
+type Stream[T any] struct {
+	w      io.Writer
+	format Format
+	// Send response headers before the body, and each item once written. Unset outside HTTP
+	header func()
+	flush  func()
+
+	started bool
+	count   int
+
+	cols   []column
+	record []string
+	csv    *csv.Writer
+	table  *tabwriter.Writer
+}
+
+func NewStream[T any](c echo.Context, format Format) *Stream[T] {
+	return &Stream[T]{
+		w:      c.Response(),
+		format: format,
+		header: func() {
+			c.Response().Header().Set(echo.HeaderContentType, contentTypes[format])
+			c.Response().WriteHeader(http.StatusOK)
+		},
+		flush: c.Response().Flush,
+	}
+}
+
+// Started reports whether the response has begun, after which its status can no longer change
+func (s *Stream[T]) Started() bool {
+	return s.started
+}
+
+// Write sends one item, sending the response headers first if it has not begun
+func (s *Stream[T]) Write(item T) (err error) {
+	if err = s.start(); err != nil {
+		return
+	}
+
+	switch s.format {
+	case CSV, TSV:
+		s.fillRecord(item)
+		if err = s.csv.Write(s.record); err != nil {
+			return
+		}
+		s.csv.Flush()
+		err = s.csv.Error()
+	case Table:
+		// Columns are aligned across every row, so rows are only written out on Close
+		s.fillRecord(item)
+		_, err = fmt.Fprintln(s.table, strings.Join(s.record, "\t"))
+	case NDJSON:
+		// One JSON object per line
+		err = json.NewEncoder(s.w).Encode(item)
+	default:
+		// Elements of a JSON array, closed by Close
+		if s.count > 0 {
+			if _, err = s.w.Write([]byte(",")); err != nil {
+				return
+			}
+		}
+		var b []byte
+		if b, err = json.Marshal(item); err != nil {
+			return
+		}
+		_, err = s.w.Write(b)
+	}
+	if err != nil {
+		return
+	}
+
+	s.count++
+	if s.flush != nil {
+		s.flush()
+	}
+	return nil
+}
+
+// Close ends the response, sending an empty list if nothing was written
+func (s *Stream[T]) Close() (err error) {
+	if err = s.start(); err != nil {
+		return
+	}
+
+	switch s.format {
+	case Table:
+		err = s.table.Flush()
+	case JSON:
+		_, err = s.w.Write([]byte("]\n"))
+	}
+	return
+}
+
+// Send the response headers and anything that comes before the first item
+func (s *Stream[T]) start() (err error) {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	if s.header != nil {
+		s.header()
+	}
+
+	switch s.format {
+	case CSV, TSV, Table:
+		s.cols = columns(reflect.TypeFor[T]())
+		s.record = make([]string, len(s.cols))
+		for i, col := range s.cols {
+			s.record[i] = col.name
+		}
+
+		if s.format == Table {
+			s.table = tabwriter.NewWriter(s.w, 0, 0, 2, ' ', 0)
+			_, err = fmt.Fprintln(s.table, strings.ToUpper(strings.Join(s.record, "\t")))
+			return
+		}
+
+		// Quote any field that contains the delimiter, a quote or a line break
+		s.csv = csv.NewWriter(s.w)
+		if s.format == TSV {
+			s.csv.Comma = '\t'
+		}
+		if err = s.csv.Write(s.record); err != nil {
+			return
+		}
+		s.csv.Flush()
+		err = s.csv.Error()
+	case JSON:
+		_, err = s.w.Write([]byte("["))
+	}
+
+	return
+}
+
+// Fill the record with the values of item in the order of its columns
+func (s *Stream[T]) fillRecord(item T) {
+	v := reflect.ValueOf(item)
+	for i, col := range s.cols {
+		s.record[i] = fmt.Sprint(v.FieldByIndex(col.index))
+	}
+}
+
+// A column of a delimited format
+type column struct {
+	name string
+	// Index of the struct field holding the column's values, as for reflect.Value.FieldByIndex
+	index []int
+}
+
+// Return the columns of a struct type, one per JSON field in field order, flattening embedded structs
+// as encoding/json does
+func columns(t reflect.Type) (cols []column) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && len(name) == 0 && field.Type.Kind() == reflect.Struct {
+			for _, col := range columns(field.Type) {
+				cols = append(cols, column{col.name, append([]int{i}, col.index...)})
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name
+		}
+		cols = append(cols, column{name, []int{i}})
+	}
+
+	return
+}
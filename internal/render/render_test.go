@@ -0,0 +1,118 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	testBase struct {
+		Article string `json:"article"`
+		Views   int32  `json:"views"`
+	}
+	testItem struct {
+		testBase
+		Canonical string `json:"canonical,omitempty"`
+		internal  string
+	}
+)
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		query    string
+		accept   string
+		expected Format
+		isValid  bool
+	}{
+		{"", "", JSON, true},
+		{"", "*/*", JSON, true},
+		{"", "text/csv", CSV, true},
+		{"", "text/html, text/tab-separated-values;q=0.9", TSV, true},
+		{"", "application/x-ndjson", NDJSON, true},
+		{"format=csv", "application/json", CSV, true},
+		{"format=ndjson", "", NDJSON, true},
+		{"format=xml", "", "", false},
+	}
+
+	e := echo.New()
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+		req.Header.Set(echo.HeaderAccept, tc.accept)
+		format, err := Negotiate(e.NewContext(req, httptest.NewRecorder()))
+
+		if (err == nil) != tc.isValid || format != tc.expected {
+			t.Errorf("Negotiate(%q, Accept %q) returns %q, %v; Expected %q", tc.query, tc.accept, format, err, tc.expected)
+		}
+	}
+}
+
+func TestItems(t *testing.T) {
+	items := []testItem{
+		{testBase: testBase{Article: "Are_You_the_One?", Views: 2}},
+		{testBase: testBase{Article: `"Weird_Al"_Yankovic`, Views: 3}, Canonical: "Weird_Al_Yankovic"},
+		{testBase: testBase{Article: "Hello,_World", Views: 4}},
+	}
+
+	testCases := []struct {
+		format              Format
+		expectedContentType string
+		expectedBody        string
+	}{
+		{CSV, "text/csv; charset=UTF-8", "article,views,canonical\nAre_You_the_One?,2,\n\"\"\"Weird_Al\"\"_Yankovic\",3,Weird_Al_Yankovic\n\"Hello,_World\",4,\n"},
+		{TSV, "text/tab-separated-values; charset=UTF-8", "article\tviews\tcanonical\nAre_You_the_One?\t2\t\n\"\"\"Weird_Al\"\"_Yankovic\"\t3\tWeird_Al_Yankovic\nHello,_World\t4\t\n"},
+		{NDJSON, "application/x-ndjson", "{\"article\":\"Are_You_the_One?\",\"views\":2}\n{\"article\":\"\\\"Weird_Al\\\"_Yankovic\",\"views\":3,\"canonical\":\"Weird_Al_Yankovic\"}\n{\"article\":\"Hello,_World\",\"views\":4}\n"},
+	}
+
+	e := echo.New()
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		if err := Items(c, tc.format, items); err != nil {
+			t.Fatalf("Items(%q) returns err = %v; Expected nil", tc.format, err)
+		}
+
+		if contentType := rec.Header().Get(echo.HeaderContentType); contentType != tc.expectedContentType {
+			t.Errorf("Items(%q) returns Content-Type %q; Expected %q", tc.format, contentType, tc.expectedContentType)
+		}
+
+		if body := rec.Body.String(); body != tc.expectedBody {
+			t.Errorf("Items(%q) returns body %q; Expected %q", tc.format, body, tc.expectedBody)
+		}
+	}
+}
+
+func TestItems_Empty(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if err := Items(c, CSV, []testItem{}); err != nil {
+		t.Fatalf("Items returns err = %v; Expected nil", err)
+	}
+
+	// An empty list still has its header row
+	if body := rec.Body.String(); body != "article,views,canonical\n" {
+		t.Errorf("Items returns body %q; Expected only the header row", body)
+	}
+}
+
+func TestWrite_Table(t *testing.T) {
+	items := []testItem{
+		{testBase: testBase{Article: "Orca", Views: 12}},
+		{testBase: testBase{Article: "Michael_Phelps", Views: 125860}, Canonical: "Michael_Phelps"},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, Table, items); err != nil {
+		t.Fatalf("Write returns err = %v; Expected nil", err)
+	}
+
+	expected := "ARTICLE         VIEWS   CANONICAL\n" +
+		"Orca            12      \n" +
+		"Michael_Phelps  125860  Michael_Phelps\n"
+	if b.String() != expected {
+		t.Errorf("Write returns %q; Expected %q", b.String(), expected)
+	}
+}
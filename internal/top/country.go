@@ -0,0 +1,193 @@
+package top
+
+import (
+	"strconv"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+// For privacy, the Wikipedia API rounds views from a country up into buckets and omits counts below a threshold.
+// Country rows therefore report views_ceil, the upper bound of the bucket, rather than an exact count
+type (
+	// CountryItem is one country's views of a project
+	CountryItem struct {
+		Project     string `json:"project"`
+		Country     string `json:"country"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		Access      string `json:"access"`
+		ViewsCeil   int64  `json:"views_ceil"`
+		Rank        int    `json:"rank"`
+	}
+
+	// CountryArticleItem is one article's views from a country
+	CountryArticleItem struct {
+		Project     string `json:"project"`
+		Article     string `json:"article"`
+		Country     string `json:"country"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		Access      string `json:"access"`
+		ViewsCeil   int64  `json:"views_ceil"`
+		Rank        int    `json:"rank"`
+	}
+
+	ByCountryParams struct {
+		Project string `query:"project"`
+		Access  string `query:"access"`
+		Date    string `query:"date"`
+	}
+
+	PerCountryParams struct {
+		Country            string `query:"country"`
+		Access             string `query:"access"`
+		Date               string `query:"date"`
+		Limit              string `query:"limit"`
+		IncludeNonArticles bool   `query:"include_non_articles"`
+	}
+)
+
+// ByCountry returns the countries sending the most views to a project in a month (date=YYYYMM)
+func (th *TopHandler) ByCountry(c echo.Context) (err error) {
+	var params ByCountryParams
+	if err = c.Bind(&params); err != nil {
+		return
+	}
+
+	if err = params.validate(); err != nil {
+		return apierror.Validation(err)
+	}
+
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+
+	countries, err := th.client.TopByCountry(c.Request().Context(), wikimedia.TopByCountryRequest{
+		Project: params.Project,
+		Access:  params.Access,
+		Year:    params.Date[:4],
+		Month:   params.Date[4:6],
+	})
+	if err != nil {
+		return apierror.From(err)
+	}
+
+	granularity, timestamp := period(params.Date)
+	items := []CountryItem{}
+	for _, country := range countries {
+		items = append(items, CountryItem{
+			Project:     trimProject(params.Project),
+			Country:     country.Country,
+			Granularity: granularity,
+			Timestamp:   timestamp,
+			Access:      params.Access,
+			ViewsCeil:   viewsCeil(country),
+			Rank:        country.Rank,
+		})
+	}
+
+	return render.Items(c, format, items)
+}
+
+// PerCountry returns the most viewed articles from one country, across every project, for a month (date=YYYYMM)
+// or a day (date=YYYYMMDD)
+func (th *TopHandler) PerCountry(c echo.Context) (err error) {
+	var params PerCountryParams
+	if err = c.Bind(&params); err != nil {
+		return
+	}
+
+	if err = params.validate(); err != nil {
+		return apierror.Validation(err)
+	}
+
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+
+	articles, err := th.client.TopPerCountry(c.Request().Context(), wikimedia.TopPerCountryRequest{
+		Country: params.Country,
+		Access:  params.Access,
+		Year:    params.Date[:4],
+		Month:   params.Date[4:6],
+		Day:     params.Date[6:],
+	})
+	if err != nil {
+		return apierror.From(err)
+	}
+
+	// Rank the remaining articles once non-article pages are filtered out, as for /top
+	granularity, timestamp := period(params.Date)
+	limit, _ := strconv.Atoi(params.Limit)
+	items := []CountryArticleItem{}
+	for _, article := range articles {
+		if !params.IncludeNonArticles && !isArticle(article.Project, article.Article) {
+			continue
+		}
+
+		items = append(items, CountryArticleItem{
+			Project:     article.Project,
+			Article:     article.Article,
+			Country:     params.Country,
+			Granularity: granularity,
+			Timestamp:   timestamp,
+			Access:      params.Access,
+			ViewsCeil:   article.ViewsCeil,
+			Rank:        len(items) + 1,
+		})
+		if len(items) == limit {
+			break
+		}
+	}
+
+	return render.Items(c, format, items)
+}
+
+// Validate params, filling in defaults
+func (p *ByCountryParams) validate() (err error) {
+	if err = paramvalidator.Project(&p.Project); err != nil {
+		return
+	}
+	if err = paramvalidator.Access(&p.Access); err != nil {
+		return
+	}
+
+	// Validate date input. The Wikipedia API only breaks down views by country per month
+	dv := paramvalidator.NewDateValidator()
+	_, err = dv.Run(p.Date)
+	return
+}
+
+// Validate params, filling in defaults
+func (p *PerCountryParams) validate() (err error) {
+	cv := paramvalidator.NewCountryValidator()
+	if _, err = cv.Run(p.Country); err != nil {
+		return
+	}
+
+	if err = paramvalidator.Access(&p.Access); err != nil {
+		return
+	}
+
+	// Validate date input, as a month or a single day
+	if err = validateDate(p.Date); err != nil {
+		return
+	}
+
+	return validateLimit(&p.Limit)
+}
+
+// Return the upper bound of a country's bucket of views. Older data only reports views, already rounded
+func viewsCeil(country wikimedia.TopCountry) int64 {
+	if country.ViewsCeil > 0 {
+		return country.ViewsCeil
+	}
+
+	return country.Views
+}
@@ -0,0 +1,102 @@
+package top
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestTopHandler_ByCountry(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		// Older data only reports views, without views_ceil
+		w.Write([]byte(`{"items":[{"countries":[
+			{"country":"US","views":13000000,"views_ceil":13500000,"rank":1},
+			{"country":"GB","views":2000000,"rank":2}
+		]}]}`))
+	}))
+	defer server.Close()
+
+	e := echo.New()
+	th := NewTopHandler(wikimedia.NewClient(server.URL, server.Client()))
+	e.GET("/top/by-country", th.ByCountry)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/top/by-country?date=202402", nil))
+
+	if expectedPath := "/pageviews/top-by-country/en.wikipedia.org/all-access/2024/02"; path != expectedPath {
+		t.Errorf("TopHandler.ByCountry requests path %q; Expected %q", path, expectedPath)
+	}
+
+	var items []CountryItem
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+		t.Fatalf("TopHandler.ByCountry returns undecodable body: %v", err)
+	}
+
+	expected := []CountryItem{
+		{Project: "en.wikipedia", Country: "US", Granularity: "monthly", Timestamp: "2024020100", Access: "all-access", ViewsCeil: 13500000, Rank: 1},
+		{Project: "en.wikipedia", Country: "GB", Granularity: "monthly", Timestamp: "2024020100", Access: "all-access", ViewsCeil: 2000000, Rank: 2},
+	}
+	if len(items) != len(expected) || items[0] != expected[0] || items[1] != expected[1] {
+		t.Errorf("TopHandler.ByCountry returns %+v; Expected %+v", items, expected)
+	}
+}
+
+func TestTopHandler_PerCountry(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"items":[{"articles":[
+			{"article":"Main_Page","project":"en.wikipedia","views_ceil":1830000,"rank":1},
+			{"article":"Super_Bowl_LVIII","project":"en.wikipedia","views_ceil":98000,"rank":2},
+			{"article":"Super_Bowl_LVIII","project":"de.wikipedia","views_ceil":12000,"rank":3}
+		]}]}`))
+	}))
+	defer server.Close()
+
+	e := echo.New()
+	th := NewTopHandler(wikimedia.NewClient(server.URL, server.Client()))
+	e.GET("/top/per-country", th.PerCountry)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/top/per-country?country=DE&date=20240211", nil))
+
+	if expectedPath := "/pageviews/top-per-country/DE/all-access/2024/02/11"; path != expectedPath {
+		t.Errorf("TopHandler.PerCountry requests path %q; Expected %q", path, expectedPath)
+	}
+
+	var items []CountryArticleItem
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+		t.Fatalf("TopHandler.PerCountry returns undecodable body: %v", err)
+	}
+
+	expected := []CountryArticleItem{
+		{Project: "en.wikipedia", Article: "Super_Bowl_LVIII", Country: "DE", Granularity: "daily", Timestamp: "2024021100", Access: "all-access", ViewsCeil: 98000, Rank: 1},
+		{Project: "de.wikipedia", Article: "Super_Bowl_LVIII", Country: "DE", Granularity: "daily", Timestamp: "2024021100", Access: "all-access", ViewsCeil: 12000, Rank: 2},
+	}
+	if len(items) != len(expected) || items[0] != expected[0] || items[1] != expected[1] {
+		t.Errorf("TopHandler.PerCountry returns %+v; Expected %+v", items, expected)
+	}
+}
+
+func TestTopHandler_PerCountryInvalidCountry(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	th := NewTopHandler(wikimedia.NewClient("http://localhost", http.DefaultClient))
+	e.GET("/top/per-country", th.PerCountry)
+
+	for _, query := range []string{"date=202402", "country=usa&date=202402", "country=US&date=2024"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/top/per-country?"+query, nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("TopHandler.PerCountry(%q) returns status %d; Expected %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
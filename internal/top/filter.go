@@ -0,0 +1,66 @@
+package top
+
+import (
+	"slices"
+	"strings"
+)
+
+type nonArticles struct {
+	// Pages that top the rankings on most days without being articles, e.g. the main page
+	pages []string
+	// Namespaces of pages that are not articles, e.g. Special for Special:Search
+	namespaces []string
+}
+
+// Non-article pages and namespaces of every project, under the English names that rankings use on English
+// projects and that every project also accepts
+var canonicalNonArticles = nonArticles{
+	pages: []string{"Main_Page", "-"},
+	namespaces: []string{
+		"Special", "Wikipedia", "File", "Portal", "Help", "Template", "Category",
+		"User", "User_talk", "Talk", "Draft", "MediaWiki", "Module",
+	},
+}
+
+// Non-article pages and namespaces under their localized names, by project language, since rankings list
+// titles as the project names them, e.g. Spezial:Suche on de.wikipedia.org
+var localizedNonArticles = map[string]nonArticles{
+	"de": {
+		pages: []string{"Wikipedia:Hauptseite"},
+		namespaces: []string{
+			"Spezial", "Datei", "Hilfe", "Vorlage", "Kategorie", "Benutzer", "Benutzerin",
+			"Benutzer_Diskussion", "Benutzerin_Diskussion", "Diskussion", "Modul",
+		},
+	},
+	"fr": {
+		pages: []string{"Wikipédia:Accueil_principal"},
+		namespaces: []string{
+			"Spécial", "Wikipédia", "Fichier", "Portail", "Aide", "Modèle", "Catégorie", "Utilisateur",
+			"Utilisatrice", "Discussion_utilisateur", "Discussion_utilisatrice", "Discussion",
+		},
+	},
+	"ja": {
+		pages: []string{"メインページ"},
+		namespaces: []string{
+			"特別", "ファイル", "ヘルプ", "利用者", "利用者‐会話", "ノート", "モジュール",
+		},
+	},
+}
+
+// Report whether a title on project, e.g. de.wikipedia.org or de.wikipedia as per-country rankings name it,
+// is an article rather than the main page or a page in a non-article namespace
+func isArticle(project, title string) bool {
+	language, _, _ := strings.Cut(project, ".")
+	localized := localizedNonArticles[language]
+
+	if slices.Contains(canonicalNonArticles.pages, title) || slices.Contains(localized.pages, title) {
+		return false
+	}
+
+	namespace, _, found := strings.Cut(title, ":")
+	if !found {
+		return true
+	}
+
+	return !slices.Contains(canonicalNonArticles.namespaces, namespace) && !slices.Contains(localized.namespaces, namespace)
+}
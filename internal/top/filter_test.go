@@ -0,0 +1,44 @@
+package top
+
+import (
+	"testing"
+)
+
+func TestIsArticle(t *testing.T) {
+	testCases := []struct {
+		project   string
+		title     string
+		isArticle bool
+	}{
+		{"en.wikipedia.org", "Albert_Einstein", true},
+		{"en.wikipedia.org", "Star_Wars:_Episode_IV_–_A_New_Hope", true},
+		{"en.wikipedia.org", "Main_Page", false},
+		{"en.wikipedia.org", "-", false},
+		{"en.wikipedia.org", "Special:Search", false},
+		{"en.wikipedia.org", "Wikipedia:Featured_pictures", false},
+		{"en.wikipedia.org", "File:Example.jpg", false},
+		{"en.wikipedia.org", "Portal:Current_events", false},
+		{"en.wikipedia.org", "User_talk:Example", false},
+		{"de.wikipedia.org", "Albert_Einstein", true},
+		{"de.wikipedia.org", "Spezial:Suche", false},
+		{"de.wikipedia.org", "Wikipedia:Hauptseite", false},
+		{"de.wikipedia.org", "Benutzer_Diskussion:Example", false},
+		{"de.wikipedia", "Datei:Example.jpg", false},
+		{"fr.wikipedia.org", "Star_Wars:_Un_nouvel_espoir", true},
+		{"fr.wikipedia.org", "Spécial:Recherche", false},
+		{"fr.wikipedia.org", "Wikipédia:Accueil_principal", false},
+		{"fr.wikipedia.org", "Catégorie:Physicien", false},
+		{"ja.wikipedia.org", "アルベルト・アインシュタイン", true},
+		{"ja.wikipedia.org", "特別:検索", false},
+		{"ja.wikipedia.org", "メインページ", false},
+		// Canonical names apply on every project, but localized ones only on their own
+		{"ja.wikipedia.org", "Special:Search", false},
+		{"en.wikipedia.org", "Spezial:Suche", true},
+	}
+
+	for _, tc := range testCases {
+		if got := isArticle(tc.project, tc.title); got != tc.isArticle {
+			t.Errorf("isArticle(%q, %q) = %t; Expected %t", tc.project, tc.title, got, tc.isArticle)
+		}
+	}
+}
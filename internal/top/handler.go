@@ -0,0 +1,159 @@
+package top
+
+import (
+	"strconv"
+	"strings"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/paramformatter"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Item is one ranked article, shaped like a /pageviews item
+	Item struct {
+		Project     string `json:"project"`
+		Article     string `json:"article"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		Access      string `json:"access"`
+		Views       int64  `json:"views"`
+		Rank        int    `json:"rank"`
+	}
+
+	Params struct {
+		Project            string `query:"project"`
+		Access             string `query:"access"`
+		Date               string `query:"date"`
+		Limit              string `query:"limit"`
+		IncludeNonArticles bool   `query:"include_non_articles"`
+	}
+
+	TopHandler struct {
+		client *wikimedia.Client
+	}
+)
+
+const (
+	// Most ranked articles the Wikipedia API returns per month or day
+	maxLimit     = 1000
+	defaultLimit = "100"
+)
+
+// List returns the most viewed articles on a project for a month (date=YYYYMM) or a day (date=YYYYMMDD)
+func (th *TopHandler) List(c echo.Context) (err error) {
+	var params Params
+	if err = c.Bind(&params); err != nil {
+		return
+	}
+
+	if err = params.validate(); err != nil {
+		return apierror.Validation(err)
+	}
+
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+
+	r := wikimedia.TopRequest{
+		Project: params.Project,
+		Access:  params.Access,
+		Year:    params.Date[:4],
+		Month:   params.Date[4:6],
+		Day:     params.Date[6:],
+	}
+	articles, err := th.client.Top(c.Request().Context(), r)
+	if err != nil {
+		return apierror.From(err)
+	}
+
+	granularity, timestamp := period(params.Date)
+
+	// Rank the remaining articles once non-article pages like Main_Page are filtered out
+	limit, _ := strconv.Atoi(params.Limit)
+	items := []Item{}
+	for _, article := range articles {
+		if !params.IncludeNonArticles && !isArticle(params.Project, article.Article) {
+			continue
+		}
+
+		items = append(items, Item{
+			Project:     trimProject(params.Project),
+			Article:     article.Article,
+			Granularity: granularity,
+			Timestamp:   timestamp,
+			Access:      params.Access,
+			Views:       article.Views,
+			Rank:        len(items) + 1,
+		})
+		if len(items) == limit {
+			break
+		}
+	}
+
+	return render.Items(c, format, items)
+}
+
+// Validate params, filling in defaults
+func (p *Params) validate() (err error) {
+	if err = paramvalidator.Project(&p.Project); err != nil {
+		return
+	}
+	if err = paramvalidator.Access(&p.Access); err != nil {
+		return
+	}
+
+	// Validate date input, as a month or a single day
+	if err = validateDate(p.Date); err != nil {
+		return
+	}
+
+	return validateLimit(&p.Limit)
+}
+
+// Validate a date param in form YYYYMM for a whole month, or YYYYMMDD for a single day
+func validateDate(date string) (err error) {
+	if len(date) == len("YYYYMMDD") {
+		dv := paramvalidator.NewDayValidator()
+		_, err = dv.Run(date)
+		return
+	}
+
+	dv := paramvalidator.NewDateValidator()
+	_, err = dv.Run(date)
+	return
+}
+
+// Validate a limit param, defaulting to the top 100
+func validateLimit(limit *string) (err error) {
+	if len(*limit) == 0 {
+		*limit = defaultLimit
+	}
+	lv := paramvalidator.NewLimitValidator(maxLimit)
+	_, err = lv.Run(*limit)
+	return
+}
+
+// Return the granularity and timestamp of a validated date, as reported for /pageviews items
+func period(date string) (granularity, timestamp string) {
+	if len(date) == len("YYYYMMDD") {
+		return "daily", date + "00"
+	}
+
+	df := paramformatter.NewDateFormatter()
+	start, _, _ := df.Run(date)
+	return "monthly", start + "00"
+}
+
+// Return a project as named in Wikipedia API responses, e.g. en.wikipedia for en.wikipedia.org
+func trimProject(project string) string {
+	return strings.TrimSuffix(project, ".org")
+}
+
+func NewTopHandler(client *wikimedia.Client) *TopHandler {
+	return &TopHandler{client: client}
+}
@@ -0,0 +1,56 @@
+package top
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestTopHandler_List(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items":[{"articles":[
+			{"article":"Main_Page","views":150000000,"rank":1},
+			{"article":"Special:Search","views":20000000,"rank":2},
+			{"article":"Super_Bowl_LVIII","views":3000000,"rank":3},
+			{"article":"Taylor_Swift","views":2000000,"rank":4},
+			{"article":"Travis_Kelce","views":1000000,"rank":5}
+		]}]}`))
+	}))
+	defer server.Close()
+
+	e := echo.New()
+	th := NewTopHandler(wikimedia.NewClient(server.URL, server.Client()))
+	e.GET("/top", th.List)
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"date=202402", []string{"Super_Bowl_LVIII", "Taylor_Swift", "Travis_Kelce"}},
+		{"date=20240211&limit=2", []string{"Super_Bowl_LVIII", "Taylor_Swift"}},
+		{"date=202402&include_non_articles=true&limit=3", []string{"Main_Page", "Special:Search", "Super_Bowl_LVIII"}},
+	}
+
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/top?"+tc.query, nil))
+
+		var items []Item
+		if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+			t.Fatalf("TopHandler.List(%q) returns undecodable body: %v", tc.query, err)
+		}
+
+		if len(items) != len(tc.expected) {
+			t.Fatalf("TopHandler.List(%q) returns %d items; Expected %d", tc.query, len(items), len(tc.expected))
+		}
+		for i, item := range items {
+			if item.Article != tc.expected[i] || item.Rank != i+1 {
+				t.Errorf("TopHandler.List(%q) item %d = %s ranked %d; Expected %s ranked %d", tc.query, i, item.Article, item.Rank, tc.expected[i], i+1)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const serviceName = "wikiviews"
+
+// Return a tracer from the global tracer provider, which does nothing until Setup installs an exporter
+func tracer() trace.Tracer {
+	return otel.Tracer(serviceName)
+}
+
+// Setup propagates W3C trace context through requests, and exports spans to the OTLP/HTTP collector at
+// endpoint if it is set, recording sampleRatio of new traces. shutdown flushes any spans not yet exported,
+// and should be called before the process exits
+func Setup(ctx context.Context, endpoint string, sampleRatio float64) (shutdown func(context.Context) error, err error) {
+	// Carry trace context from incoming requests through to upstream calls even when not exporting,
+	// so traces of a caller still join up across this service
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if len(endpoint) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Failing exports never fail requests, so only warn of them
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Warn("error exporting traces", "error", err)
+	}))
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of any span in ctx, returning a context carrying the new span.
+// The caller must end it
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartClient starts a span for a call to another service, e.g. the Wikipedia API, as Start does
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// Run calls fn inside a span named name, recording any error it returns on the span
+func Run(ctx context.Context, name string, fn func(ctx context.Context) error, attrs ...attribute.KeyValue) error {
+	ctx, span := Start(ctx, name, attrs...)
+	defer span.End()
+
+	err := fn(ctx)
+	Fail(span, err)
+	return err
+}
+
+// Fail records err on span and marks it failed, unless err is nil
+func Fail(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// Inject adds the trace context of ctx to the headers of an outgoing request
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Middleware serves every request inside a server span named by its method and route, e.g. GET /pageviews,
+// continuing the trace of any traceparent header it arrives with. The span records the status any error was
+// rendered with by apierror.Middleware
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Path()
+			if len(route) == 0 {
+				route = "unmatched"
+			}
+			ctx, span := tracer().Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+					semconv.URLQuery(req.URL.RawQuery),
+					attribute.String("wikiviews.request_id", c.Response().Header().Get(echo.HeaderXRequestID)),
+				),
+			)
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+			err = next(c)
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+
+			return
+		}
+	}
+}
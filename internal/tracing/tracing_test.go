@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Record every span ended during a test
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	if _, err := Setup(context.Background(), "", 1); err != nil {
+		t.Fatalf("Setup without an endpoint returns err = %v; Expected nil", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := newRecorder(t)
+
+	var upstreamHeader http.Header
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/pageviews", func(c echo.Context) error {
+		ctx, span := StartClient(c.Request().Context(), "GET pageviews/per-article")
+		defer span.End()
+		upstreamHeader = http.Header{}
+		Inject(ctx, upstreamHeader)
+
+		return c.NoContent(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/pageviews?article=Orca", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Middleware records %d spans; Expected 2", len(spans))
+	}
+	client, server := spans[0], spans[1]
+
+	if server.Name() != "GET /pageviews" || server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Middleware records a %s span %q; Expected a server span %q", server.SpanKind(), server.Name(), "GET /pageviews")
+	}
+
+	if traceID := server.SpanContext().TraceID().String(); traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Middleware records trace id %s; Expected the trace id of the traceparent header", traceID)
+	}
+
+	if server.Status().Code != codes.Error {
+		t.Errorf("Middleware for a 502 records span status %s; Expected %s", server.Status().Code, codes.Error)
+	}
+
+	if client.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Errorf("StartClient in a handler records a span without the server span as its parent")
+	}
+
+	expectedTraceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + client.SpanContext().SpanID().String() + "-01"
+	if traceparent := upstreamHeader.Get("traceparent"); traceparent != expectedTraceparent {
+		t.Errorf("Inject sets traceparent %q; Expected %q", traceparent, expectedTraceparent)
+	}
+}
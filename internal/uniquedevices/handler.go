@@ -0,0 +1,113 @@
+package uniquedevices
+
+import (
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/paramformatter"
+	"wikiviews/internal/paramvalidator"
+	"wikiviews/internal/render"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Item is one data point of a unique devices series, shaped like a /pageviews item
+	Item struct {
+		Project     string `json:"project"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		AccessSite  string `json:"access_site"`
+		// Devices is an estimate. Offset is the part of it estimated from devices seen only once,
+		// and Underestimate the count without them
+		Devices       int64 `json:"devices"`
+		Offset        int64 `json:"offset"`
+		Underestimate int64 `json:"underestimate"`
+	}
+
+	Params struct {
+		Project     string `query:"project"`
+		AccessSite  string `query:"access_site"`
+		Granularity string `query:"granularity"`
+		Date        string `query:"date"`
+
+		// Start and end of the month as sent to the Wikipedia API, set by validate
+		rangeStart string
+		rangeEnd   string
+	}
+
+	UniqueDevicesHandler struct {
+		client *wikimedia.Client
+	}
+)
+
+// List returns the estimated number of unique devices reading a project in one month
+func (uh *UniqueDevicesHandler) List(c echo.Context) (err error) {
+	var params Params
+	if err = c.Bind(&params); err != nil {
+		return
+	}
+
+	if err = params.validate(); err != nil {
+		return apierror.Validation(err)
+	}
+
+	format, err := render.Negotiate(c)
+	if err != nil {
+		return apierror.Validation(err)
+	}
+
+	devices, err := uh.client.UniqueDevices(c.Request().Context(), wikimedia.UniqueDevicesRequest{
+		Project:     params.Project,
+		AccessSite:  params.AccessSite,
+		Granularity: params.Granularity,
+		Start:       params.rangeStart,
+		End:         params.rangeEnd,
+	})
+	if err != nil {
+		return apierror.From(err)
+	}
+
+	// Report timestamps down to the hour, as for pageviews
+	items := []Item{}
+	for _, d := range devices {
+		items = append(items, Item{
+			Project:       d.Project,
+			Granularity:   d.Granularity,
+			Timestamp:     d.Timestamp + "00",
+			AccessSite:    d.AccessSite,
+			Devices:       d.Devices,
+			Offset:        d.Offset,
+			Underestimate: d.Underestimate,
+		})
+	}
+
+	return render.Items(c, format, items)
+}
+
+// Validate params, filling in defaults and the range boundaries the Wikipedia API needs
+func (p *Params) validate() (err error) {
+	if err = paramvalidator.Project(&p.Project); err != nil {
+		return
+	}
+	// Unique devices are not counted per hour
+	if err = paramvalidator.Granularity(&p.Granularity, paramvalidator.NewDailyGranularityValidator()); err != nil {
+		return
+	}
+	if err = paramvalidator.OrDefault(&p.AccessSite, "all-sites", paramvalidator.NewAccessSiteValidator()); err != nil {
+		return
+	}
+
+	// Validate date input, returning the first and last day of the month
+	dv := paramvalidator.NewDateValidator()
+	if _, err = dv.Run(p.Date); err != nil {
+		return
+	}
+
+	df := paramformatter.NewDateFormatter()
+	p.rangeStart, p.rangeEnd, err = df.Run(p.Date)
+	return
+}
+
+func NewUniqueDevicesHandler(client *wikimedia.Client) *UniqueDevicesHandler {
+	return &UniqueDevicesHandler{client: client}
+}
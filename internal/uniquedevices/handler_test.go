@@ -0,0 +1,50 @@
+package uniquedevices
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wikiviews/internal/apierror"
+	"wikiviews/internal/wikimedia"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestUniqueDevicesHandler_List(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"items":[{"project":"en.wikipedia","access-site":"mobile-site","granularity":"monthly","timestamp":"20240201","devices":900000000,"offset":100000000,"underestimate":800000000}]}`))
+	}))
+	defer server.Close()
+
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler
+	uh := NewUniqueDevicesHandler(wikimedia.NewClient(server.URL, server.Client()))
+	e.GET("/unique-devices", uh.List)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unique-devices?date=202402&access_site=mobile-site", nil))
+
+	if expectedPath := "/unique-devices/en.wikipedia.org/mobile-site/monthly/20240201/20240229"; path != expectedPath {
+		t.Errorf("UniqueDevicesHandler.List requests path %q; Expected %q", path, expectedPath)
+	}
+
+	var items []Item
+	if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+		t.Fatalf("UniqueDevicesHandler.List returns undecodable body: %v", err)
+	}
+
+	expected := Item{Project: "en.wikipedia", Granularity: "monthly", Timestamp: "2024020100", AccessSite: "mobile-site", Devices: 900000000, Offset: 100000000, Underestimate: 800000000}
+	if len(items) != 1 || items[0] != expected {
+		t.Errorf("UniqueDevicesHandler.List returns %+v; Expected [%+v]", items, expected)
+	}
+
+	// Unique devices are not counted per hour
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unique-devices?date=202402&granularity=hourly", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("UniqueDevicesHandler.List with hourly granularity returns status %d; Expected %d", rec.Code, http.StatusBadRequest)
+	}
+}
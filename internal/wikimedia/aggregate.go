@@ -0,0 +1,49 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type (
+	// AggregateRequest selects a pageviews series for a whole project.
+	// Start and End are formatted as the API expects for Granularity, i.e. YYYYMMDD or YYYYMMDDHH
+	AggregateRequest struct {
+		Project     string
+		Access      string
+		Agent       string
+		Granularity string
+		Start       string
+		End         string
+	}
+
+	// AggregateItem is one data point of a project-level series. Views overflow int32 for large projects
+	AggregateItem struct {
+		Project     string `json:"project"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		Access      string `json:"access"`
+		Agent       string `json:"agent"`
+		Views       int64  `json:"views"`
+	}
+)
+
+// Aggregate returns pageviews summed across every article of a project, ordered by timestamp
+func (c *Client) Aggregate(ctx context.Context, r AggregateRequest) (items []AggregateItem, err error) {
+	path := fmt.Sprintf("/pageviews/aggregate/%s/%s/%s/%s/%s/%s",
+		r.Project, r.Access, r.Agent, r.Granularity, r.Start, r.End)
+
+	var responseData struct {
+		Items []AggregateItem `json:"items"`
+	}
+	if err = c.get(ctx, "pageviews/aggregate", path, &responseData); err != nil {
+		return
+	}
+
+	sort.SliceStable(responseData.Items, func(i, j int) bool {
+		return responseData.Items[i].Timestamp < responseData.Items[j].Timestamp
+	})
+
+	return responseData.Items, nil
+}
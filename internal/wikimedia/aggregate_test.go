@@ -0,0 +1,37 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Aggregate(t *testing.T) {
+	body := `{"items":[
+		{"project":"en.wikipedia","access":"all-access","agent":"user","granularity":"daily","timestamp":"2024020200","views":250000000},
+		{"project":"en.wikipedia","access":"all-access","agent":"user","granularity":"daily","timestamp":"2024020100","views":7500000000}
+	]}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(server.URL, server.Client())
+
+	items, err := client.Aggregate(context.Background(), AggregateRequest{
+		Project:     "en.wikipedia.org",
+		Access:      "all-access",
+		Agent:       "user",
+		Granularity: "daily",
+		Start:       "20240201",
+		End:         "20240202",
+	})
+	if err != nil {
+		t.Fatalf("Client.Aggregate returns err = %v; Expected nil", err)
+	}
+
+	expectedPath := "/pageviews/aggregate/en.wikipedia.org/all-access/user/daily/20240201/20240202"
+	if path := (*requests)[0].URL.Path; path != expectedPath {
+		t.Errorf("Client.Aggregate requests path %q; Expected %q", path, expectedPath)
+	}
+
+	if len(items) != 2 || items[0].Timestamp != "2024020100" || items[0].Views != 7500000000 {
+		t.Errorf("Client.Aggregate returns items %+v; Expected two items ordered by timestamp, with views beyond int32", items)
+	}
+}
@@ -0,0 +1,67 @@
+package wikimedia
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling upstream while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open: wikimedia API is degraded")
+
+// Breaker is a circuit breaker for upstream calls. After threshold consecutive failures it opens and fails
+// fast for cooldown, then lets a single trial call through: success closes it again, failure reopens it
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+// Allow returns ErrCircuitOpen if a call should not be attempted right now
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if b.now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+
+	// Once the cooldown has passed, let one trial call through while the rest keep failing fast for another cooldown
+	b.openUntil = b.now().Add(b.cooldown)
+
+	return nil
+}
+
+// Success records a call that reached a healthy upstream, closing the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+}
+
+// Failure records a call that found upstream degraded, opening the breaker once the threshold is reached
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
@@ -0,0 +1,40 @@
+package wikimedia
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	steps := []struct {
+		description string
+		record      func()
+		advance     time.Duration
+		allowed     bool
+	}{
+		{"closed", nil, 0, true},
+		{"one failure", breaker.Failure, 0, true},
+		{"threshold reached", breaker.Failure, 0, false},
+		{"during cooldown", nil, 59 * time.Second, false},
+		{"trial after cooldown", nil, time.Second, true},
+		{"during trial", nil, 0, false},
+		{"trial failed", breaker.Failure, 0, false},
+		{"second trial", nil, time.Minute, true},
+		{"trial succeeded", breaker.Success, 0, true},
+	}
+
+	for _, step := range steps {
+		if step.record != nil {
+			step.record()
+		}
+		now = now.Add(step.advance)
+
+		if allowed := breaker.Allow() == nil; allowed != step.allowed {
+			t.Errorf("Breaker.Allow() %s returns allowed = %t; Expected %t", step.description, allowed, step.allowed)
+		}
+	}
+}
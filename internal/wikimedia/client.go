@@ -0,0 +1,237 @@
+package wikimedia
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"wikiviews/internal/logging"
+	"wikiviews/internal/metrics"
+	"wikiviews/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type (
+	// Client queries the Wikimedia REST metrics API. It is safe for concurrent use and should be shared
+	Client struct {
+		baseUrl    string
+		projectUrl string
+		httpClient *http.Client
+		retry      RetryPolicy
+		// breaker guards the metrics API. Each project's own wiki gets a breaker of its own, created on first
+		// use with the same settings, so a degraded wiki fails fast without failing calls to any other host
+		breaker         *Breaker
+		projectBreakers map[string]*Breaker
+		breakersMu      sync.Mutex
+		timeout         time.Duration
+		userAgent       string
+		sleep           func(ctx context.Context, d time.Duration) error
+	}
+
+	Option func(*Client)
+)
+
+const (
+	DefaultBaseUrl = "https://wikimedia.org/api/rest_v1/metrics"
+	// Base URL of the APIs served by each project's own wiki, with {project} replaced by the project domain
+	DefaultProjectUrl = "https://{project}/w"
+	// How long a single attempt at an upstream call may take
+	DefaultTimeout = 10 * time.Second
+
+	// Wikimedia asks clients to identify themselves with contact details, so set WithUserAgent in production
+	DefaultUserAgent = "WikiViews/1.0"
+	// Most bytes of a non-200 response body read for its detail
+	maxErrorBody = 64 << 10
+)
+
+// WithProjectUrl replaces DefaultProjectUrl, e.g. with a stub server in tests
+func WithProjectUrl(projectUrl string) Option {
+	return func(c *Client) { c.projectUrl = strings.TrimSuffix(projectUrl, "/") }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(retry RetryPolicy) Option {
+	return func(c *Client) { c.retry = retry }
+}
+
+// WithBreaker replaces the default breaker, which opens after 5 consecutive failures for 30 seconds.
+// Breakers for each project's own wiki take its threshold and cooldown
+func WithBreaker(breaker *Breaker) Option {
+	return func(c *Client) { c.breaker = breaker }
+}
+
+// WithUserAgent replaces DefaultUserAgent, e.g. with one giving contact details as Wikimedia policy asks
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithTimeout replaces DefaultTimeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.timeout = timeout }
+}
+
+// Send a GET request for path, relative to the metrics base URL, and decode the JSON response body into v.
+// endpoint names the kind of call in metrics, e.g. pageviews/per-article, since paths carry params
+func (c *Client) get(ctx context.Context, endpoint, path string, v any) error {
+	return c.getUrl(ctx, endpoint, c.breaker, c.baseUrl+path, decodeInto(v))
+}
+
+// Send a GET request for path on a project's own wiki, e.g. /rest.php/v1/search/title on en.wikipedia.org
+func (c *Client) getProject(ctx context.Context, endpoint, project, path string, v any) error {
+	return c.getUrl(ctx, endpoint, c.projectBreaker(project), strings.ReplaceAll(c.projectUrl, "{project}", project)+path, decodeInto(v))
+}
+
+// Return the breaker for calls to a project's own wiki, creating it on first use
+func (c *Client) projectBreaker(project string) *Breaker {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	breaker, ok := c.projectBreakers[project]
+	if !ok {
+		breaker = NewBreaker(c.breaker.threshold, c.breaker.cooldown)
+		c.projectBreakers[project] = breaker
+	}
+
+	return breaker
+}
+
+// Send a GET request for url and decode the response body with decode as it arrives.
+// Rate limited, 5xx and network failures are retried under the retry policy, and fail fast with
+// ErrCircuitOpen while breaker, that of url's host, is open. Non-200 responses are returned as an *APIError
+func (c *Client) getUrl(ctx context.Context, endpoint string, breaker *Breaker, url string, decode func(io.Reader) error) (err error) {
+	for attempt := 1; ; attempt++ {
+		if err = breaker.Allow(); err != nil {
+			metrics.UpstreamRejected(endpoint)
+			return
+		}
+
+		retryable, err := c.attempt(ctx, endpoint, url, decode)
+
+		// A caller that has gone away says nothing about the health of upstream
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if retryable {
+			breaker.Failure()
+		} else {
+			breaker.Success()
+		}
+
+		// Stop on success and on errors retrying cannot fix
+		if err == nil || !retryable {
+			return err
+		}
+
+		var apiErr *APIError
+		var retryAfter time.Duration
+		if errors.As(err, &apiErr) {
+			retryAfter = apiErr.RetryAfter
+		}
+
+		delay, ok := c.retry.delay(attempt, retryAfter)
+		if !ok {
+			return err
+		}
+
+		logging.FromContext(ctx).Warn("retrying upstream request", "endpoint", endpoint, "attempt", attempt, "delay_ms", delay.Milliseconds(), "error", err)
+		if sleepErr := c.sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// Make a single attempt at a GET request under the per-attempt timeout, decoding the body of a 200 response.
+// retryable reports whether the failure was a degraded upstream rather than a problem with the request
+func (c *Client) attempt(ctx context.Context, endpoint, url string, decode func(io.Reader) error) (retryable bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Trace the attempt through to the end of decoding its body
+	ctx, span := tracing.StartClient(ctx, "GET "+endpoint,
+		attribute.String("http.request.method", http.MethodGet),
+		attribute.String("url.full", url),
+	)
+	defer func() {
+		tracing.Fail(span, err)
+		span.End()
+	}()
+
+	// Create a new HTTP GET request with our User-Agent header and trace context, cancelled along with ctx
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	tracing.Inject(ctx, req.Header)
+	logger := logging.FromContext(ctx).With("endpoint", endpoint, "upstream_url", req.URL.String())
+
+	// Send the request to the Wikipedia API
+	start := time.Now()
+	response, err := c.httpClient.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		metrics.ObserveUpstream(endpoint, metrics.StatusError, duration)
+		logger.Warn("upstream request failed", "duration_ms", duration.Milliseconds(), "error", err)
+		return true, fmt.Errorf("%w: %w", ErrTransport, err)
+	}
+	defer response.Body.Close()
+	metrics.ObserveUpstream(endpoint, strconv.Itoa(response.StatusCode), duration)
+	logger.Info("upstream request", "upstream_status", response.StatusCode, "duration_ms", duration.Milliseconds())
+	span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+
+	if response.StatusCode != http.StatusOK {
+		// Error bodies are short problem documents, read whole for their detail
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+		apiErr := newAPIError(response, body)
+		return errors.Is(apiErr, ErrRateLimited) || errors.Is(apiErr, ErrServer), apiErr
+	}
+
+	// Decode the body as it arrives rather than buffering it whole. A body that fails partway is not retried,
+	// since a streaming decode may already have handed on some of its items
+	return false, tracing.Run(ctx, "decode "+endpoint, func(context.Context) error {
+		return decode(response.Body)
+	})
+}
+
+// Ping makes a single attempt at a GET request for the metrics base URL, returning an error if upstream is
+// unreachable or degraded. Any other response, even a 404, shows it is up. It bypasses retries and the
+// circuit breaker, so probing never trips the breaker nor waits out its cooldown
+func (c *Client) Ping(ctx context.Context) error {
+	retryable, err := c.attempt(ctx, "ping", c.baseUrl+"/", func(r io.Reader) error {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	})
+	if retryable {
+		return err
+	}
+
+	return nil
+}
+
+// NewClient returns a client for the metrics API at baseUrl, e.g. DefaultBaseUrl or a stub server in tests
+func NewClient(baseUrl string, httpClient *http.Client, opts ...Option) *Client {
+	c := &Client{
+		baseUrl:         strings.TrimSuffix(baseUrl, "/"),
+		projectUrl:      DefaultProjectUrl,
+		httpClient:      httpClient,
+		retry:           DefaultRetryPolicy,
+		breaker:         NewBreaker(5, 30*time.Second),
+		projectBreakers: make(map[string]*Breaker),
+		timeout:         DefaultTimeout,
+		userAgent:       DefaultUserAgent,
+		sleep:           sleep,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
@@ -0,0 +1,307 @@
+package wikimedia
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type stubResponse struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// Start a stub Wikimedia API that answers requests with responses in turn, repeating the last one
+func newStubServer(t *testing.T, responses ...stubResponse) (*httptest.Server, *[]*http.Request) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		response := responses[min(len(requests), len(responses))-1]
+		for k, v := range response.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// Return a client for server that records retry delays instead of sleeping
+func newTestClient(server *httptest.Server, delays *[]time.Duration, opts ...Option) *Client {
+	client := NewClient(server.URL, server.Client(), opts...)
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return ctx.Err()
+	}
+
+	return client
+}
+
+func TestClient_get(t *testing.T) {
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, `{"value":"ok"}`})
+	client := NewClient(server.URL+"/", server.Client())
+
+	var v struct {
+		Value string `json:"value"`
+	}
+	if err := client.get(context.Background(), "test", "/path", &v); err != nil {
+		t.Fatalf("Client.get returns err = %v; Expected nil", err)
+	}
+
+	if v.Value != "ok" {
+		t.Errorf("Client.get decodes value %q; Expected %q", v.Value, "ok")
+	}
+
+	req := (*requests)[0]
+	if req.URL.Path != "/path" {
+		t.Errorf("Client.get requests path %q; Expected %q", req.URL.Path, "/path")
+	}
+
+	if ua := req.Header.Get("User-Agent"); ua != DefaultUserAgent {
+		t.Errorf("Client.get sends User-Agent %q; Expected %q", ua, DefaultUserAgent)
+	}
+}
+
+func TestClient_get_Errors(t *testing.T) {
+	testCases := []struct {
+		status     int
+		header     http.Header
+		body       string
+		target     error
+		detail     string
+		retryAfter time.Duration
+	}{
+		{http.StatusNotFound, nil, `{"title":"Not found.","detail":"The date(s) you used are valid, but we either do not have data for those date(s)"}`, ErrNotFound, "The date(s) you used are valid, but we either do not have data for those date(s)", 0},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}, "", ErrRateLimited, "", 7 * time.Second},
+		{http.StatusInternalServerError, nil, "", ErrServer, "", 0},
+		{http.StatusServiceUnavailable, nil, "<html>", ErrServer, "", 0},
+		{http.StatusBadRequest, nil, `{"detail":"start timestamp is invalid"}`, nil, "start timestamp is invalid", 0},
+	}
+
+	for _, tc := range testCases {
+		server, _ := newStubServer(t, stubResponse{tc.status, tc.header, tc.body})
+		client := NewClient(server.URL, server.Client(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+		var v any
+		err := client.get(context.Background(), "test", "/path", &v)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("Client.get for status %d returns err = %v; Expected an *APIError", tc.status, err)
+			continue
+		}
+
+		if apiErr.StatusCode != tc.status || apiErr.Detail != tc.detail || apiErr.RetryAfter != tc.retryAfter {
+			t.Errorf("Client.get for status %d returns %+v; Expected detail %q and retry after %s", tc.status, apiErr, tc.detail, tc.retryAfter)
+		}
+
+		for _, target := range []error{ErrNotFound, ErrRateLimited, ErrServer} {
+			if errors.Is(err, target) != (target == tc.target) {
+				t.Errorf("errors.Is(Client.get for status %d, %v) returns %t; Expected %t", tc.status, target, errors.Is(err, target), target == tc.target)
+			}
+		}
+	}
+}
+
+func TestClient_get_Cancelled(t *testing.T) {
+	server, _ := newStubServer(t, stubResponse{http.StatusOK, nil, `{}`})
+	client := NewClient(server.URL, server.Client())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var v any
+	if err := client.get(ctx, "test", "/path", &v); !errors.Is(err, context.Canceled) {
+		t.Errorf("Client.get with cancelled context returns err = %v; Expected context.Canceled", err)
+	}
+}
+
+func TestClient_get_Retry(t *testing.T) {
+	server, requests := newStubServer(t,
+		stubResponse{http.StatusServiceUnavailable, nil, ""},
+		stubResponse{http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}}, ""},
+		stubResponse{http.StatusOK, nil, `{}`},
+	)
+	var delays []time.Duration
+	client := newTestClient(server, &delays)
+
+	var v any
+	if err := client.get(context.Background(), "test", "/path", &v); err != nil {
+		t.Fatalf("Client.get returns err = %v; Expected nil after retries", err)
+	}
+
+	if len(*requests) != 3 {
+		t.Errorf("Client.get sends %d requests; Expected 3", len(*requests))
+	}
+
+	if len(delays) != 2 || delays[0] > DefaultRetryPolicy.BaseDelay || delays[1] != 2*time.Second {
+		t.Errorf("Client.get waits %v between attempts; Expected a jittered delay up to %s then the 2s Retry-After", delays, DefaultRetryPolicy.BaseDelay)
+	}
+}
+
+func TestClient_get_RetryExhausted(t *testing.T) {
+	server, requests := newStubServer(t, stubResponse{http.StatusBadGateway, nil, ""})
+	var delays []time.Duration
+	client := newTestClient(server, &delays)
+
+	var v any
+	if err := client.get(context.Background(), "test", "/path", &v); !errors.Is(err, ErrServer) {
+		t.Errorf("Client.get returns err = %v; Expected ErrServer once retries are exhausted", err)
+	}
+
+	if len(*requests) != DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("Client.get sends %d requests; Expected %d", len(*requests), DefaultRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestClient_get_NoRetry(t *testing.T) {
+	testCases := []stubResponse{
+		{http.StatusNotFound, nil, ""},
+		{http.StatusBadRequest, nil, ""},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}}, ""},
+	}
+
+	for _, tc := range testCases {
+		server, requests := newStubServer(t, tc)
+		var delays []time.Duration
+		client := newTestClient(server, &delays)
+
+		var v any
+		client.get(context.Background(), "test", "/path", &v)
+
+		if len(*requests) != 1 {
+			t.Errorf("Client.get for status %d with header %v sends %d requests; Expected 1", tc.status, tc.header, len(*requests))
+		}
+	}
+}
+
+func TestClient_get_CircuitOpen(t *testing.T) {
+	server, requests := newStubServer(t, stubResponse{http.StatusInternalServerError, nil, ""})
+	var delays []time.Duration
+	client := newTestClient(server, &delays, WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithBreaker(NewBreaker(2, time.Minute)))
+
+	var v any
+	for range 2 {
+		client.get(context.Background(), "test", "/path", &v)
+	}
+
+	if err := client.get(context.Background(), "test", "/path", &v); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Client.get with breaker open returns err = %v; Expected ErrCircuitOpen", err)
+	}
+
+	if len(*requests) != 2 {
+		t.Errorf("Client.get sends %d requests; Expected 2 before the breaker opens", len(*requests))
+	}
+}
+
+func TestClient_getProject_CircuitOpen(t *testing.T) {
+	// A failing project wiki alongside a healthy metrics API
+	wiki, _ := newStubServer(t, stubResponse{http.StatusInternalServerError, nil, ""})
+	server, _ := newStubServer(t, stubResponse{http.StatusOK, nil, `{}`})
+	var delays []time.Duration
+	client := newTestClient(server, &delays, WithProjectUrl(wiki.URL+"/{project}/w"), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithBreaker(NewBreaker(2, time.Minute)))
+
+	var v any
+	for range 2 {
+		client.getProject(context.Background(), "test", "de.wikipedia.org", "/path", &v)
+	}
+
+	if err := client.getProject(context.Background(), "test", "de.wikipedia.org", "/path", &v); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Client.getProject with the wiki's breaker open returns err = %v; Expected ErrCircuitOpen", err)
+	}
+
+	// Other hosts keep their own breakers
+	if err := client.getProject(context.Background(), "test", "fr.wikipedia.org", "/path", &v); errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Client.getProject for another wiki returns err = %v; Expected its breaker closed", err)
+	}
+	if err := client.get(context.Background(), "test", "/path", &v); err != nil {
+		t.Errorf("Client.get with a wiki's breaker open returns err = %v; Expected nil", err)
+	}
+}
+
+func TestClient_get_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	var delays []time.Duration
+	client := newTestClient(server, &delays, WithTimeout(10*time.Millisecond))
+
+	var v any
+	if err := client.get(context.Background(), "test", "/path", &v); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Client.get against a hung upstream returns err = %v; Expected context.DeadlineExceeded", err)
+	}
+
+	if len(delays) != DefaultRetryPolicy.MaxAttempts-1 {
+		t.Errorf("Client.get retries %d times after timeouts; Expected %d", len(delays), DefaultRetryPolicy.MaxAttempts-1)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	testCases := []struct {
+		response  stubResponse
+		reachable bool
+	}{
+		{stubResponse{http.StatusOK, nil, ""}, true},
+		{stubResponse{http.StatusNotFound, nil, ""}, true},
+		{stubResponse{http.StatusTooManyRequests, nil, ""}, false},
+		{stubResponse{http.StatusServiceUnavailable, nil, ""}, false},
+	}
+
+	for _, tc := range testCases {
+		server, requests := newStubServer(t, tc.response)
+		var delays []time.Duration
+		client := newTestClient(server, &delays, WithBreaker(NewBreaker(1, time.Minute)))
+
+		err := client.Ping(context.Background())
+		if (err == nil) != tc.reachable {
+			t.Errorf("Client.Ping for status %d returns err = %v; Expected reachable = %t", tc.response.status, err, tc.reachable)
+		}
+
+		// Probes neither retry nor count towards the breaker
+		client.Ping(context.Background())
+		if len(*requests) != 2 {
+			t.Errorf("Client.Ping twice for status %d sends %d requests; Expected 2", tc.response.status, len(*requests))
+		}
+	}
+}
+
+func TestClient_get_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, `{"value":"ok"}`})
+	client := NewClient(server.URL, server.Client())
+
+	var v any
+	client.get(context.Background(), "test", "/path", &v)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Client.get records %d spans; Expected 2", len(spans))
+	}
+	decode, attempt := spans[0], spans[1]
+
+	if attempt.Name() != "GET test" || decode.Name() != "decode test" || decode.Parent().SpanID() != attempt.SpanContext().SpanID() {
+		t.Errorf("Client.get records spans %q and %q; Expected %q with child %q", attempt.Name(), decode.Name(), "GET test", "decode test")
+	}
+
+	expectedTraceparent := "00-" + attempt.SpanContext().TraceID().String() + "-" + attempt.SpanContext().SpanID().String() + "-01"
+	if traceparent := (*requests)[0].Header.Get("traceparent"); traceparent != expectedTraceparent {
+		t.Errorf("Client.get sends traceparent %q; Expected %q", traceparent, expectedTraceparent)
+	}
+}
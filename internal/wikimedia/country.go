@@ -0,0 +1,88 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// TopByCountryRequest selects the countries sending the most views to a project in a month
+	TopByCountryRequest struct {
+		Project string
+		Access  string
+		Year    string
+		Month   string
+	}
+
+	// TopCountry is one country's views of a project. For privacy, the Wikipedia API reports views
+	// rounded up into buckets, with ViewsCeil the upper bound of the bucket
+	TopCountry struct {
+		Country   string `json:"country"`
+		Views     int64  `json:"views"`
+		ViewsCeil int64  `json:"views_ceil"`
+		Rank      int    `json:"rank"`
+	}
+
+	// TopPerCountryRequest selects the most viewed articles from one country for a month, or a day when Day is set
+	TopPerCountryRequest struct {
+		Country string
+		Access  string
+		Year    string
+		Month   string
+		// Day is empty for a whole month
+		Day string
+	}
+
+	// TopCountryArticle is one article's views from a country, across every project. For privacy,
+	// the Wikipedia API only reports the upper bound of a bucket of views
+	TopCountryArticle struct {
+		Article   string `json:"article"`
+		Project   string `json:"project"`
+		ViewsCeil int64  `json:"views_ceil"`
+		Rank      int    `json:"rank"`
+	}
+)
+
+// TopByCountry returns the countries sending the most views to a project in a month, most views first
+func (c *Client) TopByCountry(ctx context.Context, r TopByCountryRequest) (countries []TopCountry, err error) {
+	path := fmt.Sprintf("/pageviews/top-by-country/%s/%s/%s/%s", r.Project, r.Access, r.Year, r.Month)
+
+	var responseData struct {
+		Items []struct {
+			Countries []TopCountry `json:"countries"`
+		} `json:"items"`
+	}
+	if err = c.get(ctx, "pageviews/top-by-country", path, &responseData); err != nil {
+		return
+	}
+
+	for _, item := range responseData.Items {
+		countries = append(countries, item.Countries...)
+	}
+
+	return countries, nil
+}
+
+// TopPerCountry returns the most viewed articles from one country for a month or day, most viewed first
+func (c *Client) TopPerCountry(ctx context.Context, r TopPerCountryRequest) (articles []TopCountryArticle, err error) {
+	day := r.Day
+	if len(day) == 0 {
+		day = "all-days"
+	}
+	path := fmt.Sprintf("/pageviews/top-per-country/%s/%s/%s/%s/%s", r.Country, r.Access, r.Year, r.Month, day)
+
+	var responseData struct {
+		Items []struct {
+			Articles []TopCountryArticle `json:"articles"`
+		} `json:"items"`
+	}
+	if err = c.get(ctx, "pageviews/top-per-country", path, &responseData); err != nil {
+		return
+	}
+
+	for _, item := range responseData.Items {
+		articles = append(articles, item.Articles...)
+	}
+
+	return articles, nil
+}
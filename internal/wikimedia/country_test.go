@@ -0,0 +1,63 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_TopByCountry(t *testing.T) {
+	body := `{"items":[{"project":"en.wikipedia","access":"all-access","year":"2024","month":"02","countries":[
+		{"country":"US","views":2727000000,"rank":1,"views_ceil":2727000000},
+		{"country":"GB","views":588000000,"rank":2,"views_ceil":588000000}
+	]}]}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(server.URL, server.Client())
+
+	countries, err := client.TopByCountry(context.Background(), TopByCountryRequest{Project: "en.wikipedia.org", Access: "all-access", Year: "2024", Month: "02"})
+	if err != nil {
+		t.Fatalf("Client.TopByCountry returns err = %v; Expected nil", err)
+	}
+
+	expectedPath := "/pageviews/top-by-country/en.wikipedia.org/all-access/2024/02"
+	if path := (*requests)[0].URL.Path; path != expectedPath {
+		t.Errorf("Client.TopByCountry requests path %q; Expected %q", path, expectedPath)
+	}
+
+	if len(countries) != 2 || countries[1].Country != "GB" || countries[1].ViewsCeil != 588000000 {
+		t.Errorf("Client.TopByCountry returns %+v; Expected US then GB", countries)
+	}
+}
+
+func TestClient_TopPerCountry(t *testing.T) {
+	body := `{"items":[{"country":"DE","access":"all-access","year":"2024","month":"02","day":"11","articles":[
+		{"article":"Hauptseite","project":"de.wikipedia","views_ceil":1830000,"rank":1},
+		{"article":"Super_Bowl_LVIII","project":"de.wikipedia","views_ceil":98000,"rank":2}
+	]}]}`
+
+	testCases := []struct {
+		day          string
+		expectedPath string
+	}{
+		{"", "/pageviews/top-per-country/DE/all-access/2024/02/all-days"},
+		{"11", "/pageviews/top-per-country/DE/all-access/2024/02/11"},
+	}
+
+	for _, tc := range testCases {
+		server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+		client := NewClient(server.URL, server.Client())
+
+		articles, err := client.TopPerCountry(context.Background(), TopPerCountryRequest{Country: "DE", Access: "all-access", Year: "2024", Month: "02", Day: tc.day})
+		if err != nil {
+			t.Fatalf("Client.TopPerCountry returns err = %v; Expected nil", err)
+		}
+
+		if path := (*requests)[0].URL.Path; path != tc.expectedPath {
+			t.Errorf("Client.TopPerCountry for day %q requests path %q; Expected %q", tc.day, path, tc.expectedPath)
+		}
+
+		if len(articles) != 2 || articles[1].Project != "de.wikipedia" || articles[1].ViewsCeil != 98000 {
+			t.Errorf("Client.TopPerCountry returns %+v; Expected Hauptseite then Super_Bowl_LVIII", articles)
+		}
+	}
+}
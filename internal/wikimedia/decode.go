@@ -0,0 +1,86 @@
+package wikimedia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Return a decoder of a whole JSON response body into v
+func decodeInto(v any) func(io.Reader) error {
+	return func(r io.Reader) error {
+		if err := json.NewDecoder(r).Decode(v); err != nil {
+			return decodeErr(err)
+		}
+		return nil
+	}
+}
+
+// Decode the objects of the items array of a JSON response body one at a time, e.g. {"items": [...]},
+// calling fn with each as soon as it is decoded. An error from fn stops decoding and is returned as is
+func eachItem[T any](r io.Reader, fn func(T) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return decodeErr(err)
+		}
+
+		// Skip any other field
+		if key != "items" {
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				return decodeErr(err)
+			}
+			continue
+		}
+
+		if err = expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var item T
+			if err = dec.Decode(&item); err != nil {
+				return decodeErr(err)
+			}
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+		if err = expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read the next token, which must be delim
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return decodeErr(err)
+	}
+	if token != delim {
+		return fmt.Errorf("%w: expected %s, got %v", ErrDecode, delim, token)
+	}
+
+	return nil
+}
+
+// Map an error decoding a response body. A failed read is a transport error, and anything else a body
+// that is not the JSON expected
+func decodeErr(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return fmt.Errorf("%w: error reading response: %w", ErrTransport, err)
+}
@@ -0,0 +1,68 @@
+package wikimedia
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEachItem(t *testing.T) {
+	stop := errors.New("stop")
+
+	testCases := []struct {
+		body     string
+		stopAt   int
+		expected []int
+		target   error
+	}{
+		{`{"items":[{"views":1},{"views":2},{"views":3}]}`, 0, []int{1, 2, 3}, nil},
+		{`{"meta":{"items":[9]},"items":[{"views":1}],"next":null}`, 0, []int{1}, nil},
+		{`{"items":[]}`, 0, nil, nil},
+		{`{"items":[{"views":1},{"views":2},{"views":3}]}`, 2, []int{1, 2}, stop},
+		{`{"items":[{"views":1},{"views":`, 0, []int{1}, ErrDecode},
+		{`{"items":{"views":1}}`, 0, nil, ErrDecode},
+		{`<html>`, 0, nil, ErrDecode},
+	}
+
+	for _, tc := range testCases {
+		var views []int
+		err := eachItem(strings.NewReader(tc.body), func(item struct{ Views int }) error {
+			views = append(views, item.Views)
+			if len(views) == tc.stopAt {
+				return stop
+			}
+			return nil
+		})
+
+		if !errors.Is(err, tc.target) || (tc.target == nil && err != nil) {
+			t.Errorf("eachItem(%q) returns err = %v; Expected %v", tc.body, err, tc.target)
+		}
+
+		if !slices.Equal(views, tc.expected) {
+			t.Errorf("eachItem(%q) calls fn with views %v; Expected %v", tc.body, views, tc.expected)
+		}
+	}
+}
+
+func TestClient_PerArticleEach_NoRetryAfterItems(t *testing.T) {
+	// A body cut off partway through is not retried, since its first items were already handed on
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, `{"items":[{"article":"Orca","views":1},{"arti`})
+	client := NewClient(server.URL, server.Client())
+
+	var items []ArticleItem
+	err := client.PerArticleEach(context.Background(), PerArticleRequest{Article: "Orca"}, func(item ArticleItem) error {
+		items = append(items, item)
+		return nil
+	})
+
+	if !errors.Is(err, ErrDecode) || len(items) != 1 {
+		t.Errorf("Client.PerArticleEach for a cut off body returns %d items and err = %v; Expected 1 item and ErrDecode", len(items), err)
+	}
+
+	if len(*requests) != 1 {
+		t.Errorf("Client.PerArticleEach sends %d requests; Expected 1", len(*requests))
+	}
+}
@@ -0,0 +1,50 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type (
+	// EditsRequest selects a series of edit counts for a project. Start and End are formatted as YYYYMMDD.
+	// Unlike other metrics, the Wikipedia API excludes End from the range
+	EditsRequest struct {
+		Project     string
+		EditorType  string
+		PageType    string
+		Granularity string
+		Start       string
+		End         string
+	}
+
+	// EditsResult is one data point of an edits series. Timestamp is in ISO 8601 form, e.g. 2024-02-01T00:00:00.000Z
+	EditsResult struct {
+		Timestamp string `json:"timestamp"`
+		Edits     int64  `json:"edits"`
+	}
+)
+
+// Edits returns the number of edits made to a project, ordered by timestamp
+func (c *Client) Edits(ctx context.Context, r EditsRequest) (results []EditsResult, err error) {
+	path := fmt.Sprintf("/edits/aggregate/%s/%s/%s/%s/%s/%s", r.Project, r.EditorType, r.PageType, r.Granularity, r.Start, r.End)
+
+	var responseData struct {
+		Items []struct {
+			Results []EditsResult `json:"results"`
+		} `json:"items"`
+	}
+	if err = c.get(ctx, "edits/aggregate", path, &responseData); err != nil {
+		return
+	}
+
+	for _, item := range responseData.Items {
+		results = append(results, item.Results...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp < results[j].Timestamp
+	})
+
+	return results, nil
+}
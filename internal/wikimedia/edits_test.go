@@ -0,0 +1,36 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Edits(t *testing.T) {
+	body := `{"items":[{"project":"en.wikipedia","editor-type":"user","page-type":"content","granularity":"monthly","results":[
+		{"timestamp":"2024-02-01T00:00:00.000Z","edits":3100000}
+	]}]}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(server.URL, server.Client())
+
+	results, err := client.Edits(context.Background(), EditsRequest{
+		Project:     "en.wikipedia.org",
+		EditorType:  "user",
+		PageType:    "content",
+		Granularity: "monthly",
+		Start:       "20240201",
+		End:         "20240301",
+	})
+	if err != nil {
+		t.Fatalf("Client.Edits returns err = %v; Expected nil", err)
+	}
+
+	expectedPath := "/edits/aggregate/en.wikipedia.org/user/content/monthly/20240201/20240301"
+	if path := (*requests)[0].URL.Path; path != expectedPath {
+		t.Errorf("Client.Edits requests path %q; Expected %q", path, expectedPath)
+	}
+
+	if len(results) != 1 || results[0].Timestamp != "2024-02-01T00:00:00.000Z" || results[0].Edits != 3100000 {
+		t.Errorf("Client.Edits returns %+v; Expected one monthly result", results)
+	}
+}
@@ -0,0 +1,81 @@
+package wikimedia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNotFound matches an *APIError for a 404, e.g. an unknown article or a date with no data
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches an *APIError for a 429
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer matches an *APIError for any 5xx
+	ErrServer = errors.New("server error")
+	// ErrTransport is returned when no response was received, e.g. on a network error or timeout
+	ErrTransport = errors.New("error sending request")
+	// ErrDecode is returned when a 200 response body is not the JSON expected
+	ErrDecode = errors.New("error unmarshalling JSON")
+)
+
+// APIError is a non-200 response from the Wikimedia API
+type APIError struct {
+	StatusCode int
+	URL        string
+	// Detail is the explanation from the upstream problem JSON body, when there is one
+	Detail string
+	// RetryAfter is how long the upstream asked us to wait before retrying, when it said so
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if len(e.Detail) > 0 {
+		return fmt.Sprintf("wikimedia API returned status %d for %s: %s", e.StatusCode, e.URL, e.Detail)
+	}
+
+	return fmt.Sprintf("wikimedia API returned status %d for %s", e.StatusCode, e.URL)
+}
+
+// Is lets errors.Is match an *APIError against ErrNotFound, ErrRateLimited and ErrServer
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+func newAPIError(response *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: response.StatusCode,
+		URL:        response.Request.URL.String(),
+	}
+
+	// Upstream errors are JSON problem documents, e.g. {"title": "Not found.", "detail": "..."}
+	var problem struct {
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(body, &problem) == nil {
+		apiErr.Detail = problem.Detail
+	}
+
+	// Retry-After may be given in seconds or as an HTTP date
+	if retryAfter := response.Header.Get("Retry-After"); len(retryAfter) > 0 {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			apiErr.RetryAfter = time.Until(date)
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,66 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type mediawikiPage struct {
+	Title     string `json:"title"`
+	Missing   bool   `json:"missing"`
+	Invalid   bool   `json:"invalid"`
+	Redirects []struct {
+		Title string `json:"title"`
+	} `json:"redirects"`
+}
+
+// ResolveRedirect returns the title that title redirects to on project, e.g. Michael_Phelps for Michael_phelps.
+// A title that is not a redirect is returned normalized, and one that does not exist is returned as is
+func (c *Client) ResolveRedirect(ctx context.Context, project, title string) (canonical string, err error) {
+	path := fmt.Sprintf("/api.php?action=query&format=json&formatversion=2&redirects=1&titles=%s", url.QueryEscape(title))
+
+	var responseData struct {
+		Query struct {
+			Pages []mediawikiPage `json:"pages"`
+		} `json:"query"`
+	}
+	if err = c.getProject(ctx, "query/resolve-redirect", project, path, &responseData); err != nil {
+		return
+	}
+
+	pages := responseData.Query.Pages
+	if len(pages) == 0 || pages[0].Missing || pages[0].Invalid {
+		return title, nil
+	}
+
+	return titleKey(pages[0].Title), nil
+}
+
+// Redirects returns up to limit titles in the main namespace of project that redirect to title
+func (c *Client) Redirects(ctx context.Context, project, title string, limit int) (redirects []string, err error) {
+	path := fmt.Sprintf("/api.php?action=query&format=json&formatversion=2&prop=redirects&rdnamespace=0&rdlimit=%d&titles=%s", limit, url.QueryEscape(title))
+
+	var responseData struct {
+		Query struct {
+			Pages []mediawikiPage `json:"pages"`
+		} `json:"query"`
+	}
+	if err = c.getProject(ctx, "query/redirects", project, path, &responseData); err != nil {
+		return
+	}
+
+	for _, page := range responseData.Query.Pages {
+		for _, redirect := range page.Redirects {
+			redirects = append(redirects, titleKey(redirect.Title))
+		}
+	}
+
+	return
+}
+
+// Convert a MediaWiki display title, e.g. Michael Phelps, into the form used by the pageviews API
+func titleKey(title string) string {
+	return strings.ReplaceAll(title, " ", "_")
+}
@@ -0,0 +1,52 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestClient_ResolveRedirect(t *testing.T) {
+	testCases := []struct {
+		title     string
+		body      string
+		canonical string
+	}{
+		{"Michael_phelps", `{"query":{"redirects":[{"from":"Michael phelps","to":"Michael Phelps"}],"pages":[{"pageid":19084502,"title":"Michael Phelps"}]}}`, "Michael_Phelps"},
+		{"orca", `{"query":{"normalized":[{"from":"orca","to":"Orca"}],"pages":[{"pageid":160533,"title":"Orca"}]}}`, "Orca"},
+		{"No_such_article", `{"query":{"pages":[{"title":"No such article","missing":true}]}}`, "No_such_article"},
+	}
+
+	for _, tc := range testCases {
+		server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, tc.body})
+		client := NewClient(DefaultBaseUrl, server.Client(), WithProjectUrl(server.URL+"/{project}/w"))
+
+		canonical, err := client.ResolveRedirect(context.Background(), "en.wikipedia.org", tc.title)
+		if err != nil || canonical != tc.canonical {
+			t.Errorf("Client.ResolveRedirect(%q) returns %q, err = %v; Expected %q, nil", tc.title, canonical, err, tc.canonical)
+		}
+
+		query := (*requests)[0].URL.Query()
+		if (*requests)[0].URL.Path != "/en.wikipedia.org/w/api.php" || query.Get("titles") != tc.title || query.Get("redirects") != "1" {
+			t.Errorf("Client.ResolveRedirect(%q) requests %q; Expected a redirects query for the title", tc.title, (*requests)[0].URL)
+		}
+	}
+}
+
+func TestClient_Redirects(t *testing.T) {
+	body := `{"query":{"pages":[{"pageid":19084502,"title":"Michael Phelps","redirects":[{"pageid":1,"ns":0,"title":"Michael phelps"},{"pageid":2,"ns":0,"title":"Phelps"}]}]}}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(DefaultBaseUrl, server.Client(), WithProjectUrl(server.URL+"/{project}/w"))
+
+	redirects, err := client.Redirects(context.Background(), "en.wikipedia.org", "Michael_Phelps", 50)
+	expectedRedirects := []string{"Michael_phelps", "Phelps"}
+	if err != nil || !slices.Equal(redirects, expectedRedirects) {
+		t.Errorf("Client.Redirects returns %q, err = %v; Expected %q, nil", redirects, err, expectedRedirects)
+	}
+
+	query := (*requests)[0].URL.Query()
+	if query.Get("prop") != "redirects" || query.Get("rdlimit") != "50" || query.Get("titles") != "Michael_Phelps" {
+		t.Errorf("Client.Redirects requests %q; Expected a prop=redirects query limited to 50", (*requests)[0].URL)
+	}
+}
@@ -0,0 +1,62 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+)
+
+type (
+	// PerArticleRequest selects a pageviews series for one article.
+	// Start and End are formatted as the API expects for Granularity, i.e. YYYYMMDD or YYYYMMDDHH
+	PerArticleRequest struct {
+		Project     string
+		Access      string
+		Agent       string
+		Article     string
+		Granularity string
+		Start       string
+		End         string
+	}
+
+	ArticleItem struct {
+		Project     string `json:"project"`
+		Article     string `json:"article"`
+		Granularity string `json:"granularity"`
+		Timestamp   string `json:"timestamp"`
+		Access      string `json:"access"`
+		Agent       string `json:"agent"`
+		Views       int32  `json:"views"`
+	}
+)
+
+// PerArticle returns pageviews for one article, ordered by timestamp
+func (c *Client) PerArticle(ctx context.Context, r PerArticleRequest) (items []ArticleItem, err error) {
+	err = c.PerArticleEach(ctx, r, func(item ArticleItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the range as one series ordered by timestamp
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp < items[j].Timestamp
+	})
+
+	return items, nil
+}
+
+// PerArticleEach calls fn with each data point of pageviews for one article as it is decoded from the response,
+// in the order the Wikipedia API returns them. An error from fn stops the request and is returned
+func (c *Client) PerArticleEach(ctx context.Context, r PerArticleRequest, fn func(ArticleItem) error) error {
+	path := fmt.Sprintf("/pageviews/per-article/%s/%s/%s/%s/%s/%s/%s",
+		r.Project, r.Access, r.Agent, url.QueryEscape(r.Article), r.Granularity, r.Start, r.End)
+
+	return c.getUrl(ctx, "pageviews/per-article", c.breaker, c.baseUrl+path, func(body io.Reader) error {
+		return eachItem(body, fn)
+	})
+}
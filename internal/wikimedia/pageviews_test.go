@@ -0,0 +1,38 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_PerArticle(t *testing.T) {
+	body := `{"items":[
+		{"project":"en.wikipedia","article":"Are_You_the_One?","granularity":"monthly","timestamp":"2022020100","access":"all-access","agent":"user","views":2},
+		{"project":"en.wikipedia","article":"Are_You_the_One?","granularity":"monthly","timestamp":"2022010100","access":"all-access","agent":"user","views":1}
+	]}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(server.URL, server.Client())
+
+	items, err := client.PerArticle(context.Background(), PerArticleRequest{
+		Project:     "en.wikipedia.org",
+		Access:      "all-access",
+		Agent:       "user",
+		Article:     "Are_You_the_One?",
+		Granularity: "monthly",
+		Start:       "20220101",
+		End:         "20220228",
+	})
+	if err != nil {
+		t.Fatalf("Client.PerArticle returns err = %v; Expected nil", err)
+	}
+
+	expectedPath := "/pageviews/per-article/en.wikipedia.org/all-access/user/Are_You_the_One%3F/monthly/20220101/20220228"
+	if path := (*requests)[0].URL.EscapedPath(); path != expectedPath {
+		t.Errorf("Client.PerArticle requests path %q; Expected %q", path, expectedPath)
+	}
+
+	if len(items) != 2 || items[0].Timestamp != "2022010100" || items[1].Timestamp != "2022020100" {
+		t.Errorf("Client.PerArticle returns items %+v; Expected two items ordered by timestamp", items)
+	}
+}
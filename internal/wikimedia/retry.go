@@ -0,0 +1,52 @@
+package wikimedia
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how failed upstream calls are retried
+type RetryPolicy struct {
+	// Most attempts per call, including the first
+	MaxAttempts int
+	// Delay cap for the first retry, doubling with every attempt after it
+	BaseDelay time.Duration
+	// Longest delay between attempts. A Retry-After beyond this gives up instead of waiting
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// Return how long to wait before retrying after attempt number attempt (starting at 1), and false if the call
+// should not be retried. Retry-After is honored when upstream sends one, otherwise the delay is exponential
+// backoff with full jitter
+func (rp RetryPolicy) delay(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	if attempt >= rp.MaxAttempts {
+		return 0, false
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, retryAfter <= rp.MaxDelay
+	}
+
+	ceiling := min(rp.BaseDelay<<min(attempt-1, 30), rp.MaxDelay)
+	return rand.N(ceiling + 1), true
+}
+
+// Wait for d, returning early with the context error if ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package wikimedia
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	testCases := []struct {
+		attempt    int
+		retryAfter time.Duration
+		maxDelay   time.Duration
+		ok         bool
+	}{
+		{1, 0, 100 * time.Millisecond, true},
+		{2, 0, 200 * time.Millisecond, true},
+		{3, 0, 400 * time.Millisecond, true},
+		{4, 0, 0, false},
+		{1, 500 * time.Millisecond, 500 * time.Millisecond, true},
+		{1, 2 * time.Second, 0, false},
+	}
+
+	for _, tc := range testCases {
+		for range 20 {
+			delay, ok := policy.delay(tc.attempt, tc.retryAfter)
+
+			if ok != tc.ok || (ok && (delay < 0 || delay > tc.maxDelay)) {
+				t.Errorf("RetryPolicy.delay(%d, %s) returns %s, %t; Expected up to %s, %t", tc.attempt, tc.retryAfter, delay, ok, tc.maxDelay, tc.ok)
+				break
+			}
+		}
+	}
+}
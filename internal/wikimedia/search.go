@@ -0,0 +1,32 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SearchResult is a page whose title matches a search
+type SearchResult struct {
+	// Key is the title in the form used by the pageviews API, e.g. Michael_Phelps
+	Key         string `json:"key"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// SearchTitle returns up to limit pages on project whose titles best match query, most relevant first
+func (c *Client) SearchTitle(ctx context.Context, project, query string, limit int) (results []SearchResult, err error) {
+	// Titles are searched with spaces rather than the underscores used in article params
+	q := strings.ReplaceAll(query, "_", " ")
+	path := fmt.Sprintf("/rest.php/v1/search/title?q=%s&limit=%d", url.QueryEscape(q), limit)
+
+	var responseData struct {
+		Pages []SearchResult `json:"pages"`
+	}
+	if err = c.getProject(ctx, "search/title", project, path, &responseData); err != nil {
+		return
+	}
+
+	return responseData.Pages, nil
+}
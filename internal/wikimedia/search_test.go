@@ -0,0 +1,30 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_SearchTitle(t *testing.T) {
+	body := `{"pages":[
+		{"id":1,"key":"Michael_Phelps","title":"Michael Phelps","description":"American swimmer"},
+		{"id":2,"key":"Michael_Phelps_II","title":"Michael Phelps II","description":null}
+	]}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(DefaultBaseUrl, server.Client(), WithProjectUrl(server.URL+"/{project}/w"))
+
+	results, err := client.SearchTitle(context.Background(), "en.wikipedia.org", "MICHAEL_phelps", 5)
+	if err != nil {
+		t.Fatalf("Client.SearchTitle returns err = %v; Expected nil", err)
+	}
+
+	req := (*requests)[0]
+	if req.URL.Path != "/en.wikipedia.org/w/rest.php/v1/search/title" || req.URL.Query().Get("q") != "MICHAEL phelps" || req.URL.Query().Get("limit") != "5" {
+		t.Errorf("Client.SearchTitle requests %q; Expected search for %q on en.wikipedia.org", req.URL, "MICHAEL phelps")
+	}
+
+	if len(results) != 2 || results[0].Key != "Michael_Phelps" || results[0].Description != "American swimmer" || results[1].Key != "Michael_Phelps_II" {
+		t.Errorf("Client.SearchTitle returns %+v; Expected Michael_Phelps then Michael_Phelps_II", results)
+	}
+}
@@ -0,0 +1,48 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// TopRequest selects the most viewed articles on a project for a month, or a day when Day is set
+	TopRequest struct {
+		Project string
+		Access  string
+		Year    string
+		Month   string
+		// Day is empty for a whole month
+		Day string
+	}
+
+	TopArticle struct {
+		Article string `json:"article"`
+		Views   int64  `json:"views"`
+		Rank    int    `json:"rank"`
+	}
+)
+
+// Top returns the most viewed articles for a month or day, most viewed first
+func (c *Client) Top(ctx context.Context, r TopRequest) (articles []TopArticle, err error) {
+	day := r.Day
+	if len(day) == 0 {
+		day = "all-days"
+	}
+	path := fmt.Sprintf("/pageviews/top/%s/%s/%s/%s/%s", r.Project, r.Access, r.Year, r.Month, day)
+
+	var responseData struct {
+		Items []struct {
+			Articles []TopArticle `json:"articles"`
+		} `json:"items"`
+	}
+	if err = c.get(ctx, "pageviews/top", path, &responseData); err != nil {
+		return
+	}
+
+	for _, item := range responseData.Items {
+		articles = append(articles, item.Articles...)
+	}
+
+	return articles, nil
+}
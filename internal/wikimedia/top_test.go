@@ -0,0 +1,40 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Top(t *testing.T) {
+	body := `{"items":[{"project":"en.wikipedia","access":"all-access","year":"2024","month":"02","day":"all-days","articles":[
+		{"article":"Main_Page","views":150000000,"rank":1},
+		{"article":"Super_Bowl_LVIII","views":3000000,"rank":2}
+	]}]}`
+
+	testCases := []struct {
+		day          string
+		expectedPath string
+	}{
+		{"", "/pageviews/top/en.wikipedia.org/all-access/2024/02/all-days"},
+		{"11", "/pageviews/top/en.wikipedia.org/all-access/2024/02/11"},
+	}
+
+	for _, tc := range testCases {
+		server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+		client := NewClient(server.URL, server.Client())
+
+		articles, err := client.Top(context.Background(), TopRequest{Project: "en.wikipedia.org", Access: "all-access", Year: "2024", Month: "02", Day: tc.day})
+		if err != nil {
+			t.Fatalf("Client.Top returns err = %v; Expected nil", err)
+		}
+
+		if path := (*requests)[0].URL.Path; path != tc.expectedPath {
+			t.Errorf("Client.Top for day %q requests path %q; Expected %q", tc.day, path, tc.expectedPath)
+		}
+
+		if len(articles) != 2 || articles[1].Article != "Super_Bowl_LVIII" || articles[1].Rank != 2 {
+			t.Errorf("Client.Top returns %+v; Expected Main_Page then Super_Bowl_LVIII", articles)
+		}
+	}
+}
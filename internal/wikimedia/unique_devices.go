@@ -0,0 +1,49 @@
+package wikimedia
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type (
+	// UniqueDevicesRequest selects a unique devices series for a project.
+	// Start and End are formatted as YYYYMMDD, and both included
+	UniqueDevicesRequest struct {
+		Project     string
+		AccessSite  string
+		Granularity string
+		Start       string
+		End         string
+	}
+
+	// UniqueDevicesItem is one data point of a unique devices series. Devices is an estimate, with Offset the
+	// part of it estimated from devices seen only once and Underestimate the count without them
+	UniqueDevicesItem struct {
+		Project       string `json:"project"`
+		AccessSite    string `json:"access-site"`
+		Granularity   string `json:"granularity"`
+		Timestamp     string `json:"timestamp"`
+		Devices       int64  `json:"devices"`
+		Offset        int64  `json:"offset"`
+		Underestimate int64  `json:"underestimate"`
+	}
+)
+
+// UniqueDevices returns the estimated number of unique devices reading a project, ordered by timestamp
+func (c *Client) UniqueDevices(ctx context.Context, r UniqueDevicesRequest) (items []UniqueDevicesItem, err error) {
+	path := fmt.Sprintf("/unique-devices/%s/%s/%s/%s/%s", r.Project, r.AccessSite, r.Granularity, r.Start, r.End)
+
+	var responseData struct {
+		Items []UniqueDevicesItem `json:"items"`
+	}
+	if err = c.get(ctx, "unique-devices", path, &responseData); err != nil {
+		return
+	}
+
+	sort.SliceStable(responseData.Items, func(i, j int) bool {
+		return responseData.Items[i].Timestamp < responseData.Items[j].Timestamp
+	})
+
+	return responseData.Items, nil
+}
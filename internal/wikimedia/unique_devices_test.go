@@ -0,0 +1,36 @@
+package wikimedia
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_UniqueDevices(t *testing.T) {
+	body := `{"items":[
+		{"project":"en.wikipedia","access-site":"all-sites","granularity":"daily","timestamp":"20240202","devices":72000000,"offset":9000000,"underestimate":63000000},
+		{"project":"en.wikipedia","access-site":"all-sites","granularity":"daily","timestamp":"20240201","devices":71000000,"offset":8000000,"underestimate":63000000}
+	]}`
+	server, requests := newStubServer(t, stubResponse{http.StatusOK, nil, body})
+	client := NewClient(server.URL, server.Client())
+
+	items, err := client.UniqueDevices(context.Background(), UniqueDevicesRequest{
+		Project:     "en.wikipedia.org",
+		AccessSite:  "all-sites",
+		Granularity: "daily",
+		Start:       "20240201",
+		End:         "20240202",
+	})
+	if err != nil {
+		t.Fatalf("Client.UniqueDevices returns err = %v; Expected nil", err)
+	}
+
+	expectedPath := "/unique-devices/en.wikipedia.org/all-sites/daily/20240201/20240202"
+	if path := (*requests)[0].URL.Path; path != expectedPath {
+		t.Errorf("Client.UniqueDevices requests path %q; Expected %q", path, expectedPath)
+	}
+
+	if len(items) != 2 || items[0].Timestamp != "20240201" || items[0].AccessSite != "all-sites" || items[0].Offset != 8000000 {
+		t.Errorf("Client.UniqueDevices returns items %+v; Expected two items ordered by timestamp", items)
+	}
+}